@@ -12,6 +12,9 @@ import (
 // It can be mocked with tools like https://github.com/golang/mock in unit tests.
 type Client interface {
 	Execute(ctx context.Context, query string, params map[string]interface{}, header http.Header) (*http.Response, error)
+	ExecutePersisted(ctx context.Context, query string, hash string, params map[string]interface{}, header http.Header) (*http.Response, error)
+	ExecutePersistedMutation(ctx context.Context, query string, hash string, params map[string]interface{}, header http.Header) (*http.Response, error)
+	Subscribe(ctx context.Context, query string, params map[string]interface{}, header http.Header) (<-chan SubscriptionMessage, error)
 }
 
 // client is a private struct that can be created with New function.
@@ -21,24 +24,107 @@ type client struct {
 
 	// httpClient is a pointer to an instance of http.Client. It can be fully customized to provide authentication mechanism, timeout etc.
 	httpClient *http.Client
+
+	// subscriptionTransport specifies the transport used to execute GraphQL subscriptions.
+	subscriptionTransport SubscriptionTransport
+
+	// persistedQueries specifies if operations are sent as automatic persisted queries.
+	persistedQueries bool
+
+	// persistedQueriesGET specifies if hashed queries are sent with HTTP GET method.
+	persistedQueriesGET bool
+
+	// persistedOnly specifies if only the hash of the operation is sent - the query is never sent as a fallback.
+	persistedOnly bool
+
+	// maxMessageSize limits the size of a message received over any subscription transport.
+	maxMessageSize int64
+}
+
+// SubscriptionTransport specifies the transport used to execute GraphQL subscriptions.
+type SubscriptionTransport int
+
+const (
+	// WebSocketTransport executes GraphQL subscriptions over WebSocket using graphql-transport-ws protocol. It is the default transport.
+	WebSocketTransport SubscriptionTransport = iota
+	// SSETransport executes GraphQL subscriptions over Server-Sent Events.
+	SSETransport
+)
+
+// DefaultMaxMessageSize is the default limit of the size of a message received over subscription transport (32 MiB).
+const DefaultMaxMessageSize int64 = 32 << 20
+
+// errPersistedOperationsOnly is returned when the query would be sent to the server while only persisted operations are allowed.
+var errPersistedOperationsOnly = GraphQLCallError{"Preparation of GraphQL call failed", "only persisted operations are allowed - query cannot be sent"}
+
+// Option configures optional properties of the client.
+type Option func(*client)
+
+// WithSubscriptionTransport sets the transport used to execute GraphQL subscriptions.
+func WithSubscriptionTransport(transport SubscriptionTransport) Option {
+	return func(c *client) {
+		c.subscriptionTransport = transport
+	}
+}
+
+// WithPersistedQueries enables automatic persisted queries - operations are sent as SHA-256 hash first and full query is sent only if the server does not know the hash.
+// If useGET is true, hashed operations executed with ExecutePersisted are sent with HTTP GET method so they can be cached.
+// Mutations executed with ExecutePersistedMutation are always sent with HTTP POST method.
+func WithPersistedQueries(useGET bool) Option {
+	return func(c *client) {
+		c.persistedQueries = true
+		c.persistedQueriesGET = useGET
+	}
+}
+
+// WithPersistedOperationsOnly enables sending only the ID (SHA-256 hash) of the operation instead of the query.
+// It is meant for servers accepting only operations allow-listed with persisted operations manifest generated by grafikgen.
+// PersistedQueryNotFound error is returned to the caller instead of sending the query.
+// Execute and Subscribe fail, as they always send the query.
+func WithPersistedOperationsOnly() Option {
+	return func(c *client) {
+		c.persistedQueries = true
+		c.persistedOnly = true
+	}
+}
+
+// WithMaxMessageSize sets the limit of the size (in bytes) of a message received over subscription transport -
+// WebSocket message or Server-Sent Event (or single GraphQL result sent instead of the event stream).
+// Subscription fails with an error when the server sends larger message. Zero means no limit. Defaults to DefaultMaxMessageSize.
+func WithMaxMessageSize(size int64) Option {
+	return func(c *client) {
+		c.maxMessageSize = size
+	}
 }
 
 // New endpoint creates an instance of the client.
-func New(endpoint string, httpClient *http.Client) Client {
-	return &client{
-		endpoint:   endpoint,
-		httpClient: httpClient,
+func New(endpoint string, httpClient *http.Client, opts ...Option) Client {
+	c := &client{
+		endpoint:       endpoint,
+		httpClient:     httpClient,
+		maxMessageSize: DefaultMaxMessageSize,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Execute is a receiver function used by generated grafik client to execute HTTP requests.
 // Caller method is responsible for closing the body reader.
 func (c *client) Execute(ctx context.Context, query string, params map[string]interface{}, header http.Header) (*http.Response, error) {
-	q := c.formatQuery(query)
+	if c.persistedOnly {
+		return nil, errPersistedOperationsOnly
+	}
 	req := GraphQLRequest{
-		Query:     q,
+		Query:     query,
 		Variables: params,
 	}
+	return c.post(ctx, req, header)
+}
+
+// post sends GraphQL request as JSON body of HTTP POST request.
+func (c *client) post(ctx context.Context, req GraphQLRequest, header http.Header) (*http.Response, error) {
 	reqJSON, err := json.Marshal(req)
 	if err != nil {
 		return nil, GraphQLCallError{"Parsing GraphQL request failed", err.Error()}
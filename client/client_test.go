@@ -7,10 +7,11 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 )
 
+const continentQuery = `query getContinentNameByCode($code:ID!){continent(code:$code){code name}}`
+
 func TestClient_Execute_DefaultHeader_Success(t *testing.T) {
 	t.Parallel()
 	exp := createCountriesResponse()
@@ -18,18 +19,10 @@ func TestClient_Execute_DefaultHeader_Success(t *testing.T) {
 		handleRequest(t, exp, w, r)
 	}))
 
-	query := `
-    query getContinentNameByCode($code: ID!) {
-        continent(code: $code) {
-            code,
-            name
-        }
-    }
-`
 	client := New(svr.URL, svr.Client())
 	params := make(map[string]interface{})
 	params["code"] = "EU"
-	res, err := client.Execute(context.TODO(), query, params, nil)
+	res, err := client.Execute(context.TODO(), continentQuery, params, nil)
 	assert.NoError(t, err)
 
 	b, err := io.ReadAll(res.Body)
@@ -55,18 +48,10 @@ func TestClient_Execute_CustomHeader_Success(t *testing.T) {
 		handleRequest(t, exp, w, r)
 	}))
 
-	query := `
-    query($code: ID!) {
-        continent(code: $code) {
-            code,
-            name
-        }
-    }
-`
 	client := New(svr.URL, svr.Client())
 	params := make(map[string]interface{})
 	params["code"] = "EU"
-	res, err := client.Execute(context.TODO(), query, params, expHeader)
+	res, err := client.Execute(context.TODO(), continentQuery, params, expHeader)
 	assert.NoError(t, err)
 
 	b, err := io.ReadAll(res.Body)
@@ -144,9 +129,7 @@ func handleRequest(t *testing.T, exp countriesResponse, w http.ResponseWriter, r
 	assert.Equal(t, "EU", graphqlReq.Variables["code"])
 
 	// Check payload - query.
-	assert.NotNil(t, graphqlReq.Query)
-	assert.False(t, strings.Contains(graphqlReq.Query, "\n"))
-	assert.False(t, strings.Contains(graphqlReq.Query, "\t"))
+	assert.Equal(t, continentQuery, graphqlReq.Query)
 }
 
 func createCountriesResponse() countriesResponse {
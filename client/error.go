@@ -1,7 +1,11 @@
 // Package client contains the code used internally by grafik to prepare & send HTTP requests.
 package client
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 // GraphQLCallError is a root level error struct generated by grafik.
 // It corresponds to GraphQL HTTP error response as per specification: http://spec.graphql.org/October2021/#sec-Errors.Error-result-format
@@ -13,3 +17,39 @@ type GraphQLCallError struct {
 func (e GraphQLCallError) Error() string {
 	return fmt.Sprintf("GraphQL call failed. Message=%s Reason=%s", e.Message, e.Reason)
 }
+
+// GraphQLResponseError is returned by generated grafik client when GraphQL endpoint responded with non-2xx HTTP status or with populated errors array.
+// Use errors.As to access HTTP status and all GraphQL errors. Partial data is still decoded into the generated response struct.
+// StatusCode is 0 for errors received over GraphQL subscription.
+type GraphQLResponseError struct {
+	StatusCode int
+	Errors     []GraphQLError
+}
+
+func (e GraphQLResponseError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, gqlErr := range e.Errors {
+		msgs[i] = gqlErr.Message
+	}
+	return fmt.Sprintf("GraphQL response contains errors. Status=%d Errors=[%s]", e.StatusCode, strings.Join(msgs, "; "))
+}
+
+// GraphQLError represents single entry of the errors array in GraphQL response.
+// See http://spec.graphql.org/October2021/#sec-Errors.Error-result-format
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLErrorLocation `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLErrorLocation represents location of the GraphQL error in the GraphQL operation.
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// isSuccessStatus returns true if HTTP status code is 2xx.
+func isSuccessStatus(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
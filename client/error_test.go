@@ -15,3 +15,17 @@ func TestGraphQLCallError_Error(t *testing.T) {
 
 	assert.Equal(t, expErrMsg, err.Error())
 }
+
+func TestGraphQLResponseError_Error(t *testing.T) {
+	t.Parallel()
+	err := GraphQLResponseError{
+		StatusCode: 200,
+		Errors: []GraphQLError{
+			{Message: "Cannot query field \"id\" on type \"Rocket\"."},
+			{Message: "Internal server error"},
+		},
+	}
+	expErrMsg := "GraphQL response contains errors. Status=200 Errors=[Cannot query field \"id\" on type \"Rocket\".; Internal server error]"
+
+	assert.Equal(t, expErrMsg, err.Error())
+}
@@ -1,9 +1,14 @@
 // Package client contains the code used internally by grafik to prepare & send HTTP requests.
 package client
 
-import "strings"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
 
-// formatQuery provides simple GraphQL code compression.
-func (c *client) formatQuery(query string) string {
-	return strings.Join(strings.Fields(strings.TrimSpace(query)), " ")
+// PersistedQueryHash returns SHA-256 hash of the query sent by the client, used as persisted query identifier.
+// It is used by grafikgen to precompute hashes of GraphQL operations. The query is sent exactly as generated, so it is hashed without any changes.
+func PersistedQueryHash(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
 }
@@ -0,0 +1,33 @@
+// Package client contains the code used internally by grafik to prepare & send HTTP requests.
+package client
+
+import (
+	"encoding/json"
+)
+
+// MarshalInput is a function used by generated grafik client to marshal GraphQL input object with optional fields.
+// Fields with their JSON name set to true in unset map are not sent at all.
+// Fields with their JSON name listed in nullFields are sent as explicit null.
+// v must not implement json.Marshaler itself to avoid infinite recursion.
+func MarshalInput(v interface{}, unset map[string]bool, nullFields []string) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for name, isUnset := range unset {
+		if isUnset {
+			delete(fields, name)
+		}
+	}
+	for _, name := range nullFields {
+		fields[name] = json.RawMessage("null")
+	}
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMarshalInput(t *testing.T) {
+	t.Parallel()
+	name := "Falcon 9"
+	tests := []struct {
+		v          rocketInput
+		unset      map[string]bool
+		nullFields []string
+		exp        string
+	}{
+		{
+			rocketInput{Name: &name, Tags: []string{}},
+			map[string]bool{"name": false, "tags": false},
+			nil,
+			`{"active":false,"name":"Falcon 9","tags":[]}`,
+		},
+		{
+			rocketInput{Active: true},
+			map[string]bool{"name": true, "tags": true},
+			nil,
+			`{"active":true}`,
+		},
+		{
+			rocketInput{Active: true},
+			map[string]bool{"name": true, "tags": true},
+			[]string{"name"},
+			`{"active":true,"name":null}`,
+		},
+	}
+	for _, test := range tests {
+		b, err := MarshalInput(test.v, test.unset, test.nullFields)
+		assert.NoError(t, err)
+		assert.JSONEq(t, test.exp, string(b))
+	}
+}
+
+func TestMarshalInput_Error(t *testing.T) {
+	t.Parallel()
+	_, err := MarshalInput(make(chan int), nil, nil)
+
+	assert.EqualError(t, err, "json: unsupported type: chan int")
+}
+
+type rocketInput struct {
+	Name   *string  `json:"name"`
+	Active bool     `json:"active"`
+	Tags   []string `json:"tags"`
+}
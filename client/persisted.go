@@ -0,0 +1,143 @@
+// Package client contains the code used internally by grafik to prepare & send HTTP requests.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// persistedQueryVersion is a version of automatic persisted queries protocol.
+const persistedQueryVersion = 1
+
+// Error message and code returned by the server when it does not know the hash of the persisted query.
+const (
+	persistedQueryNotFoundMsg  = "PersistedQueryNotFound"
+	persistedQueryNotFoundCode = "PERSISTED_QUERY_NOT_FOUND"
+)
+
+// ExecutePersisted is a receiver function used by generated grafik client to execute operations as automatic persisted queries.
+// hash is SHA-256 hash of the query precomputed by grafikgen. If persisted queries are not enabled with WithPersistedQueries it behaves as Execute.
+// Caller method is responsible for closing the body reader.
+func (c *client) ExecutePersisted(ctx context.Context, query string, hash string, params map[string]interface{}, header http.Header) (*http.Response, error) {
+	return c.executePersisted(ctx, query, hash, params, header, c.persistedQueriesGET)
+}
+
+// ExecutePersistedMutation is a receiver function used by generated grafik client to execute mutations as automatic persisted queries.
+// It behaves as ExecutePersisted, except the mutation is never sent with HTTP GET method.
+// Caller method is responsible for closing the body reader.
+func (c *client) ExecutePersistedMutation(ctx context.Context, query string, hash string, params map[string]interface{}, header http.Header) (*http.Response, error) {
+	return c.executePersisted(ctx, query, hash, params, header, false)
+}
+
+// executePersisted sends the hash of the operation and sends full query only if the server does not know the hash.
+// If useGET is true, the hash is sent with HTTP GET method.
+func (c *client) executePersisted(ctx context.Context, query string, hash string, params map[string]interface{}, header http.Header, useGET bool) (*http.Response, error) {
+	if !c.persistedQueries {
+		return c.Execute(ctx, query, params, header)
+	}
+
+	req := GraphQLRequest{
+		Variables: params,
+		Extensions: &GraphQLRequestExtensions{
+			PersistedQuery: &PersistedQuery{
+				Version:    persistedQueryVersion,
+				Sha256Hash: hash,
+			},
+		},
+	}
+
+	var httpRes *http.Response
+	var err error
+	if useGET {
+		httpRes, err = c.get(ctx, req, header)
+	} else {
+		httpRes, err = c.post(ctx, req, header)
+	}
+	if err != nil || c.persistedOnly {
+		return httpRes, err
+	}
+
+	notFound, err := isPersistedQueryNotFound(httpRes)
+	if err != nil {
+		return nil, GraphQLCallError{"Reading GraphQL response failed", err.Error()}
+	}
+	if !notFound {
+		return httpRes, nil
+	}
+
+	// Server does not know the hash yet - send full query to register it.
+	req.Query = query
+	return c.post(ctx, req, header)
+}
+
+// get sends GraphQL request as URL query parameters of HTTP GET request.
+func (c *client) get(ctx context.Context, req GraphQLRequest, header http.Header) (*http.Response, error) {
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, GraphQLCallError{"Preparation of GraphQL call failed", err.Error()}
+	}
+
+	values := u.Query()
+	if req.Query != "" {
+		values.Set("query", req.Query)
+	}
+	if req.Variables != nil {
+		variables, err := json.Marshal(req.Variables)
+		if err != nil {
+			return nil, GraphQLCallError{"Parsing GraphQL request failed", err.Error()}
+		}
+		values.Set("variables", string(variables))
+	}
+	if req.Extensions != nil {
+		extensions, err := json.Marshal(req.Extensions)
+		if err != nil {
+			return nil, GraphQLCallError{"Parsing GraphQL request failed", err.Error()}
+		}
+		values.Set("extensions", string(extensions))
+	}
+	u.RawQuery = values.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, GraphQLCallError{"Preparation of GraphQL call failed", err.Error()}
+	}
+
+	if header != nil {
+		httpReq.Header = header
+	}
+	// Content-Type is set so the request is not considered simple request by CSRF prevention of the server.
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpRes, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return httpRes, GraphQLCallError{"GraphQL call failed", err.Error()}
+	}
+
+	return httpRes, nil
+}
+
+// isPersistedQueryNotFound checks if the server responded with PersistedQueryNotFound error.
+// The body of the response is read, closed and replaced, so it can still be read by the caller.
+func isPersistedQueryNotFound(res *http.Response) (bool, error) {
+	b, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return false, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(b))
+
+	var envelope errorsEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return false, nil
+	}
+	for _, gqlErr := range envelope.Errors {
+		if gqlErr.Message == persistedQueryNotFoundMsg || gqlErr.Extensions["code"] == persistedQueryNotFoundCode {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const persistedQuery = `query getContinentNameByCode($code:ID!){continent(code:$code){code name}}`
+
+func TestPersistedQueryHash(t *testing.T) {
+	t.Parallel()
+	// Query is hashed exactly as it is sent.
+	assert.NotEqual(t, PersistedQueryHash("query { continents { code } }"), PersistedQueryHash("query{continents{code}}"))
+	assert.Equal(t, "50b4964a4781befa9c3a78700df68b6efaaf43ee2737ea00afbc886f62b0a838", PersistedQueryHash("query { continents { code } }"))
+}
+
+func TestClient_ExecutePersisted_Disabled(t *testing.T) {
+	t.Parallel()
+	exp := createCountriesResponse()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := readPersistedRequest(t, r)
+		assert.Nil(t, req.Extensions)
+		assert.NotEmpty(t, req.Query)
+		writeJSON(t, w, exp)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client())
+	res, err := client.ExecutePersisted(context.TODO(), persistedQuery, PersistedQueryHash(persistedQuery), map[string]interface{}{"code": "EU"}, nil)
+	assert.NoError(t, err)
+
+	var countriesRes countriesResponse
+	assert.NoError(t, DecodeResponse(res, &countriesRes))
+	assert.EqualValues(t, exp, countriesRes)
+}
+
+func TestClient_ExecutePersisted_HashFound(t *testing.T) {
+	t.Parallel()
+	exp := createCountriesResponse()
+	hash := PersistedQueryHash(persistedQuery)
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, http.MethodPost, r.Method)
+		req := readPersistedRequest(t, r)
+		assert.Empty(t, req.Query)
+		assert.Equal(t, &PersistedQuery{Version: 1, Sha256Hash: hash}, req.Extensions.PersistedQuery)
+		assert.Equal(t, "EU", req.Variables["code"])
+		writeJSON(t, w, exp)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithPersistedQueries(false))
+	res, err := client.ExecutePersisted(context.TODO(), persistedQuery, hash, map[string]interface{}{"code": "EU"}, nil)
+	assert.NoError(t, err)
+
+	var countriesRes countriesResponse
+	assert.NoError(t, DecodeResponse(res, &countriesRes))
+	assert.EqualValues(t, exp, countriesRes)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_ExecutePersisted_HashNotFound(t *testing.T) {
+	t.Parallel()
+	exp := createCountriesResponse()
+	hash := PersistedQueryHash(persistedQuery)
+	var mu sync.Mutex
+	reqs := make([]GraphQLRequest, 0)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		req := readPersistedRequest(t, r)
+		reqs = append(reqs, req)
+		if req.Query == "" {
+			_, _ = io.WriteString(w, `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`)
+			return
+		}
+		writeJSON(t, w, exp)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithPersistedQueries(false))
+	res, err := client.ExecutePersisted(context.TODO(), persistedQuery, hash, map[string]interface{}{"code": "EU"}, nil)
+	assert.NoError(t, err)
+
+	var countriesRes countriesResponse
+	assert.NoError(t, DecodeResponse(res, &countriesRes))
+	assert.EqualValues(t, exp, countriesRes)
+
+	assert.Len(t, reqs, 2)
+	assert.Empty(t, reqs[0].Query)
+	assert.Equal(t, persistedQuery, reqs[1].Query)
+	assert.Equal(t, hash, reqs[1].Extensions.PersistedQuery.Sha256Hash)
+}
+
+func TestClient_ExecutePersisted_OperationsOnly(t *testing.T) {
+	t.Parallel()
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		req := readPersistedRequest(t, r)
+		assert.Empty(t, req.Query)
+		_, _ = io.WriteString(w, `{"errors":[{"message":"PersistedQueryNotFound"}]}`)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithPersistedOperationsOnly())
+	res, err := client.ExecutePersisted(context.TODO(), persistedQuery, PersistedQueryHash(persistedQuery), map[string]interface{}{"code": "EU"}, nil)
+	assert.NoError(t, err)
+
+	var countriesRes countriesResponse
+	err = DecodeResponse(res, &countriesRes)
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, []GraphQLError{{Message: "PersistedQueryNotFound"}}, resErr.Errors)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_Execute_OperationsOnly(t *testing.T) {
+	t.Parallel()
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithPersistedOperationsOnly())
+	res, err := client.Execute(context.TODO(), persistedQuery, map[string]interface{}{"code": "EU"}, nil)
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, errPersistedOperationsOnly)
+	assert.Equal(t, 0, calls)
+}
+
+func TestClient_Subscribe_OperationsOnly(t *testing.T) {
+	t.Parallel()
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer svr.Close()
+
+	for _, transport := range []SubscriptionTransport{WebSocketTransport, SSETransport} {
+		client := New(svr.URL, svr.Client(), WithPersistedOperationsOnly(), WithSubscriptionTransport(transport))
+		msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+		assert.Nil(t, msgs)
+		assert.ErrorIs(t, err, errPersistedOperationsOnly)
+	}
+	assert.Equal(t, 0, calls)
+}
+
+func TestClient_ExecutePersisted_GET(t *testing.T) {
+	t.Parallel()
+	exp := createCountriesResponse()
+	hash := PersistedQueryHash(persistedQuery)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Empty(t, r.URL.Query().Get("query"))
+		assert.Equal(t, `{"code":"EU"}`, r.URL.Query().Get("variables"))
+		assert.Equal(t, `{"persistedQuery":{"version":1,"sha256Hash":"`+hash+`"}}`, r.URL.Query().Get("extensions"))
+		writeJSON(t, w, exp)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithPersistedQueries(true))
+	res, err := client.ExecutePersisted(context.TODO(), persistedQuery, hash, map[string]interface{}{"code": "EU"}, nil)
+	assert.NoError(t, err)
+
+	var countriesRes countriesResponse
+	assert.NoError(t, DecodeResponse(res, &countriesRes))
+	assert.EqualValues(t, exp, countriesRes)
+}
+
+func TestClient_ExecutePersistedMutation_GET(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = io.WriteString(w, `{"data":{}}`)
+	}))
+	defer svr.Close()
+
+	// Operation type is passed by the caller - the query text is not inspected.
+	query := "query { launches { id } }"
+	mutation := "# Launches rocket.\nmutation { launch { id } }"
+	client := New(svr.URL, svr.Client(), WithPersistedQueries(true))
+	res, err := client.ExecutePersistedMutation(context.TODO(), query, PersistedQueryHash(query), nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	res, err = client.ExecutePersistedMutation(context.TODO(), mutation, PersistedQueryHash(mutation), nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, []string{http.MethodPost, http.MethodPost}, methods)
+}
+
+func TestClient_ExecutePersistedMutation_HashNotFound(t *testing.T) {
+	t.Parallel()
+	exp := createCountriesResponse()
+	hash := PersistedQueryHash(persistedQuery)
+	var reqs []GraphQLRequest
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		req := readPersistedRequest(t, r)
+		reqs = append(reqs, req)
+		if req.Query == "" {
+			_, _ = io.WriteString(w, `{"errors":[{"message":"PersistedQueryNotFound"}]}`)
+			return
+		}
+		writeJSON(t, w, exp)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithPersistedQueries(true))
+	res, err := client.ExecutePersistedMutation(context.TODO(), persistedQuery, hash, map[string]interface{}{"code": "EU"}, nil)
+	assert.NoError(t, err)
+
+	var countriesRes countriesResponse
+	assert.NoError(t, DecodeResponse(res, &countriesRes))
+	assert.EqualValues(t, exp, countriesRes)
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, persistedQuery, reqs[1].Query)
+	assert.Equal(t, hash, reqs[1].Extensions.PersistedQuery.Sha256Hash)
+}
+
+func readPersistedRequest(t *testing.T, r *http.Request) GraphQLRequest {
+	b, err := io.ReadAll(r.Body)
+	assert.NoError(t, err)
+	var req GraphQLRequest
+	assert.NoError(t, json.Unmarshal(b, &req))
+	return req
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	_, err = w.Write(b)
+	assert.NoError(t, err)
+}
@@ -4,6 +4,19 @@ package client
 // GraphQLRequest is a root level struct generated by grafik.
 // It corresponds to GraphQL HTTP request as per specification: https://graphql.org/learn/serving-over-http/#post-request
 type GraphQLRequest struct {
-	Query     string                 `json:"query"`
-	Variables map[string]interface{} `json:"variables"`
+	Query      string                    `json:"query,omitempty"`
+	Variables  map[string]interface{}    `json:"variables"`
+	Extensions *GraphQLRequestExtensions `json:"extensions,omitempty"`
+}
+
+// GraphQLRequestExtensions represents extensions of GraphQL request.
+type GraphQLRequestExtensions struct {
+	PersistedQuery *PersistedQuery `json:"persistedQuery,omitempty"`
+}
+
+// PersistedQuery identifies automatic persisted query by the hash of the query.
+// See https://www.apollographql.com/docs/apollo-server/performance/apq/
+type PersistedQuery struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
 }
@@ -0,0 +1,85 @@
+// Package client contains the code used internally by grafik to prepare & send HTTP requests.
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// MarshalFragments is a function used by generated grafik client to marshal struct embedding structs of named fragments.
+// Fields of v and of all fragments are merged into single JSON object - fields selected by multiple fragments are written once.
+// v must not implement json.Marshaler itself to avoid infinite recursion.
+func MarshalFragments(v interface{}, fragments ...interface{}) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+	for _, part := range append([]interface{}{v}, fragments...) {
+		b, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// errorsEnvelope is used to decode the errors array of GraphQL response independently of the generated response struct.
+type errorsEnvelope struct {
+	Errors []GraphQLError `json:"errors"`
+}
+
+// DecodeResponse is a function used by generated grafik client to decode HTTP response into the generated response struct.
+// It reads and closes the body of the response.
+// If HTTP status is not 2xx or response contains GraphQL errors it returns GraphQLResponseError - v still holds partial data,
+// so generated methods return the decoded response alongside GraphQLResponseError (and nil response alongside any other error).
+func DecodeResponse(res *http.Response, v interface{}) error {
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return GraphQLCallError{"Reading GraphQL response failed", err.Error()}
+	}
+
+	var envelope errorsEnvelope
+	unmarshalErr := json.Unmarshal(b, v)
+	if unmarshalErr == nil {
+		_ = json.Unmarshal(b, &envelope)
+	}
+
+	if !isSuccessStatus(res.StatusCode) {
+		return GraphQLResponseError{
+			StatusCode: res.StatusCode,
+			Errors:     envelope.Errors,
+		}
+	}
+
+	if unmarshalErr != nil {
+		return GraphQLCallError{"Parsing GraphQL response failed", unmarshalErr.Error()}
+	}
+
+	if len(envelope.Errors) > 0 {
+		return GraphQLResponseError{
+			StatusCode: res.StatusCode,
+			Errors:     envelope.Errors,
+		}
+	}
+
+	return nil
+}
+
+// DecodePayload is a function used by generated grafik client to decode single GraphQL result (i.e. subscription event) into the generated struct.
+// If payload contains GraphQL errors it returns GraphQLResponseError - v still holds partial data.
+func DecodePayload(payload json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return GraphQLCallError{"Parsing GraphQL response failed", err.Error()}
+	}
+
+	var envelope errorsEnvelope
+	_ = json.Unmarshal(payload, &envelope)
+	if len(envelope.Errors) > 0 {
+		return GraphQLResponseError{Errors: envelope.Errors}
+	}
+
+	return nil
+}
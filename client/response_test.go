@@ -0,0 +1,182 @@
+package client
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecodeResponse_Success(t *testing.T) {
+	t.Parallel()
+	body := &trackingReadCloser{Reader: strings.NewReader(`{"data":{"continent":{"code":"EU","name":"Europe"}}}`)}
+	res := &http.Response{StatusCode: http.StatusOK, Body: body}
+
+	var countriesRes countriesResponse
+	err := DecodeResponse(res, &countriesRes)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, createCountriesResponse(), countriesRes)
+	assert.True(t, body.closed)
+}
+
+func TestDecodeResponse_Read_Error(t *testing.T) {
+	t.Parallel()
+	body := &trackingReadCloser{Reader: faultyReader{}}
+	res := &http.Response{StatusCode: http.StatusOK, Body: body}
+
+	var countriesRes countriesResponse
+	err := DecodeResponse(res, &countriesRes)
+
+	expErr := GraphQLCallError{
+		Message: "Reading GraphQL response failed",
+		Reason:  "unit test: Failed to read",
+	}
+	assert.ErrorIs(t, err, expErr)
+	assert.True(t, body.closed)
+}
+
+func TestDecodeResponse_Unmarshal_Error(t *testing.T) {
+	t.Parallel()
+	body := &trackingReadCloser{Reader: strings.NewReader(`{"data":`)}
+	res := &http.Response{StatusCode: http.StatusOK, Body: body}
+
+	var countriesRes countriesResponse
+	err := DecodeResponse(res, &countriesRes)
+
+	expErr := GraphQLCallError{
+		Message: "Parsing GraphQL response failed",
+		Reason:  "unexpected end of JSON input",
+	}
+	assert.ErrorIs(t, err, expErr)
+	assert.True(t, body.closed)
+}
+
+func TestDecodeResponse_GraphQLErrors_PartialData(t *testing.T) {
+	t.Parallel()
+	body := &trackingReadCloser{Reader: strings.NewReader(`{
+		"data": {"continent": {"code": "EU", "name": "Europe"}},
+		"errors": [{
+			"message": "Population is unavailable",
+			"locations": [{"line": 4, "column": 13}],
+			"path": ["continent", "population"],
+			"extensions": {"code": "INTERNAL_SERVER_ERROR"}
+		}]
+	}`)}
+	res := &http.Response{StatusCode: http.StatusOK, Body: body}
+
+	var countriesRes countriesResponse
+	err := DecodeResponse(res, &countriesRes)
+
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, http.StatusOK, resErr.StatusCode)
+	assert.Equal(t, []GraphQLError{
+		{
+			Message:    "Population is unavailable",
+			Locations:  []GraphQLErrorLocation{{Line: 4, Column: 13}},
+			Path:       []interface{}{"continent", "population"},
+			Extensions: map[string]interface{}{"code": "INTERNAL_SERVER_ERROR"},
+		},
+	}, resErr.Errors)
+	assert.EqualValues(t, createCountriesResponse(), countriesRes)
+	assert.True(t, body.closed)
+}
+
+func TestDecodeResponse_NonSuccessStatus_GraphQLErrors(t *testing.T) {
+	t.Parallel()
+	body := &trackingReadCloser{Reader: strings.NewReader(`{"errors": [{"message": "Variable \"$code\" of required type \"ID!\" was not provided."}]}`)}
+	res := &http.Response{StatusCode: http.StatusBadRequest, Body: body}
+
+	var countriesRes countriesResponse
+	err := DecodeResponse(res, &countriesRes)
+
+	expErr := GraphQLResponseError{
+		StatusCode: http.StatusBadRequest,
+		Errors:     []GraphQLError{{Message: "Variable \"$code\" of required type \"ID!\" was not provided."}},
+	}
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, expErr, resErr)
+	assert.True(t, body.closed)
+}
+
+func TestDecodeResponse_NonSuccessStatus_NonJSONBody(t *testing.T) {
+	t.Parallel()
+	body := &trackingReadCloser{Reader: strings.NewReader("502 Bad Gateway")}
+	res := &http.Response{StatusCode: http.StatusBadGateway, Body: body}
+
+	var countriesRes countriesResponse
+	err := DecodeResponse(res, &countriesRes)
+
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, http.StatusBadGateway, resErr.StatusCode)
+	assert.Empty(t, resErr.Errors)
+	assert.True(t, body.closed)
+}
+
+func TestMarshalFragments(t *testing.T) {
+	t.Parallel()
+	type rocketIds struct {
+		Id string `json:"id"`
+	}
+	type rocketInfo struct {
+		rocketIds `json:"-"`
+		Id        string `json:"id"`
+		Name      string `json:"name"`
+	}
+	type rocket struct {
+		rocketInfo `json:"-"`
+		Country    string `json:"country"`
+	}
+	v := rocket{rocketInfo: rocketInfo{rocketIds: rocketIds{Id: "1"}, Id: "1", Name: "Falcon 9"}, Country: "USA"}
+
+	b, err := MarshalFragments(v, v.rocketInfo, v.rocketInfo.rocketIds)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"country":"USA","id":"1","name":"Falcon 9"}`, string(b))
+}
+
+func TestMarshalFragments_Error(t *testing.T) {
+	t.Parallel()
+	_, err := MarshalFragments(struct{}{}, make(chan int))
+	assert.EqualError(t, err, "json: unsupported type: chan int")
+}
+
+type trackingReadCloser struct {
+	io.Reader
+	closed bool
+}
+
+func (r *trackingReadCloser) Close() error {
+	r.closed = true
+	return nil
+}
+
+type faultyReader struct{}
+
+func (faultyReader) Read([]byte) (int, error) {
+	return 0, errors.New("unit test: Failed to read")
+}
+
+func TestDecodePayload_Success(t *testing.T) {
+	t.Parallel()
+	var countriesRes countriesResponse
+	err := DecodePayload([]byte(`{"data":{"continent":{"code":"EU","name":"Europe"}}}`), &countriesRes)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, createCountriesResponse(), countriesRes)
+}
+
+func TestDecodePayload_GraphQLErrors_PartialData(t *testing.T) {
+	t.Parallel()
+	var countriesRes countriesResponse
+	err := DecodePayload([]byte(`{"data":{"continent":{"code":"EU","name":"Europe"}},"errors":[{"message":"Field 'capital' not found"}]}`), &countriesRes)
+
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, []GraphQLError{{Message: "Field 'capital' not found"}}, resErr.Errors)
+	assert.EqualValues(t, createCountriesResponse(), countriesRes)
+}
@@ -0,0 +1,180 @@
+// Package client contains the code used internally by grafik to prepare & send HTTP requests.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sseContentType is a content type of Server-Sent Events stream.
+const sseContentType = "text/event-stream"
+
+// sseEvent represents single Server-Sent Event.
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+// subscribeSSE executes GraphQL subscription over Server-Sent Events.
+// See https://github.com/enisdenjo/graphql-sse/blob/master/PROTOCOL.md#distinct-connections-mode
+func (c *client) subscribeSSE(ctx context.Context, payload json.RawMessage, header http.Header) (<-chan SubscriptionMessage, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, GraphQLCallError{"Preparation of GraphQL call failed", err.Error()}
+	}
+
+	if header != nil {
+		httpReq.Header = header.Clone()
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", sseContentType)
+
+	httpRes, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, GraphQLCallError{"GraphQL call failed", err.Error()}
+	}
+
+	if !isSuccessStatus(httpRes.StatusCode) {
+		return nil, DecodeResponse(httpRes, &errorsEnvelope{})
+	}
+
+	msgs := make(chan SubscriptionMessage)
+	go func() {
+		defer close(msgs)
+		defer httpRes.Body.Close()
+
+		// Server may respond with single GraphQL result instead of the stream - i.e. when the operation is invalid.
+		mediaType, _, _ := mime.ParseMediaType(httpRes.Header.Get("Content-Type"))
+		if mediaType != sseContentType {
+			b, err := readLimited(httpRes.Body, c.maxMessageSize)
+			if err != nil {
+				sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"Reading GraphQL response failed", err.Error()}})
+				return
+			}
+			sendMessage(ctx, msgs, SubscriptionMessage{Payload: b})
+			return
+		}
+
+		receiveSSE(ctx, httpRes.Body, msgs, c.maxMessageSize)
+	}()
+	return msgs, nil
+}
+
+// receiveSSE reads Server-Sent Events and sends them to msgs until the subscription is completed, failed or ctx is cancelled.
+// Event larger than maxSize bytes fails the subscription. Zero maxSize means no limit.
+func receiveSSE(ctx context.Context, body io.Reader, msgs chan<- SubscriptionMessage, maxSize int64) {
+	reader := bufio.NewReader(body)
+	for {
+		event, err := readSSEEvent(reader, maxSize)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, io.EOF) {
+			sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"GraphQL subscription failed", "connection closed by the server"}})
+			return
+		}
+		if err != nil {
+			sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"GraphQL subscription failed", err.Error()}})
+			return
+		}
+
+		switch event.Name {
+		case msgNext:
+			if !sendMessage(ctx, msgs, SubscriptionMessage{Payload: json.RawMessage(event.Data)}) {
+				return
+			}
+		case msgComplete:
+			return
+		}
+	}
+}
+
+// readSSEEvent reads lines of the stream until blank line that dispatches the event.
+// Lines of the event (including comments) must not exceed maxSize bytes in total.
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+func readSSEEvent(reader *bufio.Reader, maxSize int64) (sseEvent, error) {
+	var event sseEvent
+	data := make([]string, 0)
+	var size int64
+	for {
+		limit := int64(-1)
+		if maxSize > 0 {
+			limit = maxSize - size
+		}
+		line, err := readSSELine(reader, limit)
+		if errors.Is(err, errLineTooLong) {
+			return event, fmt.Errorf("Server-Sent Event exceeds maximum size of %d bytes", maxSize)
+		}
+		if err != nil {
+			return event, err
+		}
+		size += int64(len(line))
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if event.Name == "" && len(data) == 0 {
+				// Blank line without any event - i.e. after keep-alive comment.
+				size = 0
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
+
+// errLineTooLong is returned by readSSELine when the line exceeds the limit.
+var errLineTooLong = errors.New("line too long")
+
+// readSSELine reads single line of the stream including the line break.
+// Reading stops with errLineTooLong as soon as the line exceeds limit bytes, so long line is never read into memory. Negative limit means no limit.
+func readSSELine(reader *bufio.Reader, limit int64) (string, error) {
+	line := make([]byte, 0)
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if limit >= 0 && int64(len(line)+len(chunk)) > limit {
+			return "", errLineTooLong
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
+
+// readLimited reads the whole body, but fails if it is larger than maxSize bytes. Zero maxSize means no limit.
+func readLimited(body io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize == 0 {
+		return io.ReadAll(body)
+	}
+	b, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > maxSize {
+		return nil, fmt.Errorf("response exceeds maximum size of %d bytes", maxSize)
+	}
+	return b, nil
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Subscribe_SSE_Success(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, sseContentType, r.Header.Get("Accept"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var req GraphQLRequest
+		assert.NoError(t, json.Unmarshal(b, &req))
+		assert.Equal(t, "subscription onLaunch($id:ID!){launched(id:$id){name}}", req.Query)
+		assert.Equal(t, "EU", req.Variables["id"])
+
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		_, _ = io.WriteString(w, ": keep-alive\n\n")
+		_, _ = io.WriteString(w, "event: next\ndata: {\"data\":{\"launched\":{\"name\":\"Falcon 1\"}}}\n\n")
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, "event: next\r\ndata: {\"data\":\r\ndata: {\"launched\":{\"name\":\"Falcon 9\"}}}\r\n\r\n")
+		_, _ = io.WriteString(w, "event: complete\ndata:\n\n")
+	}))
+	defer svr.Close()
+
+	query := `subscription onLaunch($id:ID!){launched(id:$id){name}}`
+	client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport))
+	params := map[string]interface{}{"id": "EU"}
+	header := http.Header{"Authorization": {"Bearer token"}}
+	msgs, err := client.Subscribe(context.TODO(), query, params, header)
+	assert.NoError(t, err)
+
+	payloads := make([]string, 0)
+	for msg := range msgs {
+		assert.NoError(t, msg.Err)
+		payloads = append(payloads, string(msg.Payload))
+	}
+	assert.Equal(t, []string{
+		`{"data":{"launched":{"name":"Falcon 1"}}}`,
+		"{\"data\":\n{\"launched\":{\"name\":\"Falcon 9\"}}}",
+	}, payloads)
+}
+
+func TestClient_Subscribe_SSE_SingleResult(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"errors":[{"message":"Unknown rocket"}]}`)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport))
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	assert.NoError(t, msg.Err)
+	assert.Equal(t, `{"errors":[{"message":"Unknown rocket"}]}`, string(msg.Payload))
+
+	_, open := <-msgs
+	assert.False(t, open)
+}
+
+func TestClient_Subscribe_SSE_ConnectionClosed(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", sseContentType)
+		_, _ = io.WriteString(w, "event: next\ndata: {\"data\":{}}\n\n")
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport))
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	assert.NoError(t, msg.Err)
+	assert.Equal(t, `{"data":{}}`, string(msg.Payload))
+
+	msg = <-msgs
+	expErr := GraphQLCallError{
+		Message: "GraphQL subscription failed",
+		Reason:  "connection closed by the server",
+	}
+	assert.ErrorIs(t, msg.Err, expErr)
+}
+
+func TestClient_Subscribe_SSE_MessageTooLarge(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		stream string
+	}{
+		{"Long line", "event: next\ndata: " + strings.Repeat("a", 1000) + "\n\n"},
+		{"Many lines", "event: next\n" + strings.Repeat("data: 0123456789\n", 10) + "\n"},
+	}
+
+	for _, test := range tests {
+		stream := test.stream
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", sseContentType)
+			_, _ = io.WriteString(w, strings.Repeat(": keep-alive\n\n", 10))
+			_, _ = io.WriteString(w, "event: next\ndata: {\"data\":{}}\n\n")
+			_, _ = io.WriteString(w, stream)
+		}))
+
+		client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport), WithMaxMessageSize(64))
+		msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+		assert.NoError(t, err, test.name)
+
+		msg := <-msgs
+		assert.NoError(t, msg.Err, test.name)
+		assert.Equal(t, `{"data":{}}`, string(msg.Payload), test.name)
+
+		msg = <-msgs
+		expErr := GraphQLCallError{
+			Message: "GraphQL subscription failed",
+			Reason:  "Server-Sent Event exceeds maximum size of 64 bytes",
+		}
+		assert.ErrorIs(t, msg.Err, expErr, test.name)
+		svr.Close()
+	}
+}
+
+func TestClient_Subscribe_SSE_SingleResult_TooLarge(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"errors":[{"message":"`+strings.Repeat("a", 100)+`"}]}`)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport), WithMaxMessageSize(64))
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	expErr := GraphQLCallError{
+		Message: "Reading GraphQL response failed",
+		Reason:  "response exceeds maximum size of 64 bytes",
+	}
+	assert.ErrorIs(t, msg.Err, expErr)
+}
+
+func TestClient_Subscribe_SSE_ContextCancel(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", sseContentType)
+		_, _ = io.WriteString(w, "event: next\ndata: {\"data\":{}}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer svr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport))
+	msgs, err := client.Subscribe(ctx, "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	assert.NoError(t, msg.Err)
+	cancel()
+
+	_, open := <-msgs
+	assert.False(t, open)
+}
+
+func TestClient_Subscribe_SSE_Status_Error(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"errors":[{"message":"Unauthorized"}]}`)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithSubscriptionTransport(SSETransport))
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+
+	assert.Nil(t, msgs)
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, http.StatusUnauthorized, resErr.StatusCode)
+	assert.Equal(t, []GraphQLError{{Message: "Unauthorized"}}, resErr.Errors)
+}
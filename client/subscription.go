@@ -0,0 +1,212 @@
+// Package client contains the code used internally by grafik to prepare & send HTTP requests.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SubscriptionProtocol is a WebSocket sub-protocol used by grafik to execute GraphQL subscriptions.
+// See https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const SubscriptionProtocol = "graphql-transport-ws"
+
+// subscriptionID is an ID of the subscription. Each subscription uses separate WebSocket connection.
+const subscriptionID = "1"
+
+// graphql-transport-ws message types.
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgError          = "error"
+	msgComplete       = "complete"
+)
+
+// SubscriptionMessage is a single event of GraphQL subscription.
+// Payload holds GraphQL execution result (data & errors). Err is set if subscription failed - it is the last message sent.
+type SubscriptionMessage struct {
+	Payload json.RawMessage
+	Err     error
+}
+
+// wsMessage represents graphql-transport-ws message.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Subscribe is a receiver function used by generated grafik client to execute GraphQL subscriptions.
+// The subscription is executed over WebSocket or Server-Sent Events depending on the subscription transport of the client.
+// Returned channel is closed when the server completes the subscription, the subscription fails or ctx is cancelled.
+func (c *client) Subscribe(ctx context.Context, query string, params map[string]interface{}, header http.Header) (<-chan SubscriptionMessage, error) {
+	if c.persistedOnly {
+		return nil, errPersistedOperationsOnly
+	}
+	payload, err := json.Marshal(GraphQLRequest{
+		Query:     query,
+		Variables: params,
+	})
+	if err != nil {
+		return nil, GraphQLCallError{"Parsing GraphQL request failed", err.Error()}
+	}
+
+	if c.subscriptionTransport == SSETransport {
+		return c.subscribeSSE(ctx, payload, header)
+	}
+	return c.subscribeWebSocket(ctx, payload, header)
+}
+
+// subscribeWebSocket executes GraphQL subscription over WebSocket.
+// The endpoint of the client is used with ws(s) scheme. The header is sent with WebSocket handshake request.
+func (c *client) subscribeWebSocket(ctx context.Context, payload json.RawMessage, header http.Header) (<-chan SubscriptionMessage, error) {
+	conn, err := dialWebSocket(ctx, c.endpoint, header, SubscriptionProtocol, c.tlsConfig())
+	if err != nil {
+		return nil, GraphQLCallError{"Opening GraphQL subscription failed", err.Error()}
+	}
+	conn.maxMessageSize = c.maxMessageSize
+
+	// Close connection when ctx is cancelled to unblock pending reads.
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.writeJSON(wsMessage{ID: subscriptionID, Type: msgComplete})
+			_ = conn.close()
+		case <-stop:
+		}
+	}()
+
+	if err := initSubscription(conn, payload); err != nil {
+		close(stop)
+		_ = conn.close()
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, GraphQLCallError{"GraphQL subscription handshake failed", err.Error()}
+	}
+
+	msgs := make(chan SubscriptionMessage)
+	go func() {
+		defer close(msgs)
+		defer close(stop)
+		defer conn.close()
+		receive(ctx, conn, msgs)
+	}()
+	return msgs, nil
+}
+
+// tlsConfig returns TLS config of http.Client transport, so the same certificates are used by WebSocket connection.
+func (c *client) tlsConfig() *tls.Config {
+	if c.httpClient == nil {
+		return nil
+	}
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+		return transport.TLSClientConfig
+	}
+	return nil
+}
+
+// initSubscription initializes graphql-transport-ws connection and starts the subscription.
+func initSubscription(conn *wsConn, payload json.RawMessage) error {
+	if err := conn.writeJSON(wsMessage{Type: msgConnectionInit}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := conn.readJSON()
+		if err != nil {
+			return err
+		}
+		if msg.Type == msgPing {
+			if err := conn.writeJSON(wsMessage{Type: msgPong}); err != nil {
+				return err
+			}
+			continue
+		}
+		if msg.Type != msgConnectionAck {
+			return fmt.Errorf("expected %s message, got %s", msgConnectionAck, msg.Type)
+		}
+		break
+	}
+
+	return conn.writeJSON(wsMessage{ID: subscriptionID, Type: msgSubscribe, Payload: payload})
+}
+
+// receive reads subscription messages and sends them to msgs until the subscription is completed, failed or ctx is cancelled.
+func receive(ctx context.Context, conn *wsConn, msgs chan<- SubscriptionMessage) {
+	for {
+		msg, err := conn.readJSON()
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, io.EOF) {
+			sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"GraphQL subscription failed", "connection closed by the server"}})
+			return
+		}
+		if err != nil {
+			sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"GraphQL subscription failed", err.Error()}})
+			return
+		}
+
+		switch msg.Type {
+		case msgNext:
+			if !sendMessage(ctx, msgs, SubscriptionMessage{Payload: msg.Payload}) {
+				return
+			}
+		case msgError:
+			var gqlErrs []GraphQLError
+			if err := json.Unmarshal(msg.Payload, &gqlErrs); err != nil {
+				sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"Parsing GraphQL subscription error failed", err.Error()}})
+				return
+			}
+			sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLResponseError{Errors: gqlErrs}})
+			return
+		case msgComplete:
+			return
+		case msgPing:
+			if err := conn.writeJSON(wsMessage{Type: msgPong}); err != nil {
+				sendMessage(ctx, msgs, SubscriptionMessage{Err: GraphQLCallError{"GraphQL subscription failed", err.Error()}})
+				return
+			}
+		}
+	}
+}
+
+// sendMessage sends msg to msgs unless ctx is cancelled. It returns false if ctx is cancelled.
+func sendMessage(ctx context.Context, msgs chan<- SubscriptionMessage, msg SubscriptionMessage) bool {
+	select {
+	case msgs <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// writeJSON writes graphql-transport-ws message as WebSocket text message.
+func (c *wsConn) writeJSON(msg wsMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.writeMessage(wsOpText, b)
+}
+
+// readJSON reads graphql-transport-ws message from WebSocket.
+func (c *wsConn) readJSON() (wsMessage, error) {
+	var msg wsMessage
+	_, b, err := c.readMessage()
+	if err != nil {
+		return msg, err
+	}
+	err = json.Unmarshal(b, &msg)
+	return msg, err
+}
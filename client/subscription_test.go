@@ -0,0 +1,248 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Subscribe_Success(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		conn := acceptWebSocket(t, w, r)
+		defer conn.close()
+		ackSubscription(t, conn)
+
+		msg, err := conn.readJSON()
+		assert.NoError(t, err)
+		assert.Equal(t, msgSubscribe, msg.Type)
+		var req GraphQLRequest
+		assert.NoError(t, json.Unmarshal(msg.Payload, &req))
+		assert.Equal(t, "subscription onLaunch($id:ID!){launched(id:$id){name}}", req.Query)
+		assert.Equal(t, "EU", req.Variables["id"])
+
+		assert.NoError(t, conn.writeJSON(wsMessage{Type: msgPing}))
+		assert.NoError(t, conn.writeJSON(wsMessage{ID: msg.ID, Type: msgNext, Payload: json.RawMessage(`{"data":{"launched":{"name":"Falcon 1"}}}`)}))
+		assert.NoError(t, conn.writeJSON(wsMessage{ID: msg.ID, Type: msgNext, Payload: json.RawMessage(`{"data":{"launched":{"name":"Falcon 9"}}}`)}))
+		assert.NoError(t, conn.writeJSON(wsMessage{ID: msg.ID, Type: msgComplete}))
+		waitForClose(conn)
+	}))
+	defer svr.Close()
+
+	query := `subscription onLaunch($id:ID!){launched(id:$id){name}}`
+	client := New(svr.URL, svr.Client())
+	params := map[string]interface{}{"id": "EU"}
+	header := http.Header{"Authorization": {"Bearer token"}}
+	msgs, err := client.Subscribe(context.TODO(), query, params, header)
+	assert.NoError(t, err)
+
+	payloads := make([]string, 0)
+	for msg := range msgs {
+		assert.NoError(t, msg.Err)
+		payloads = append(payloads, string(msg.Payload))
+	}
+	assert.Equal(t, []string{
+		`{"data":{"launched":{"name":"Falcon 1"}}}`,
+		`{"data":{"launched":{"name":"Falcon 9"}}}`,
+	}, payloads)
+}
+
+func TestClient_Subscribe_GraphQLError(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebSocket(t, w, r)
+		defer conn.close()
+		ackSubscription(t, conn)
+
+		msg, err := conn.readJSON()
+		assert.NoError(t, err)
+		assert.NoError(t, conn.writeJSON(wsMessage{ID: msg.ID, Type: msgError, Payload: json.RawMessage(`[{"message":"Unknown rocket"}]`)}))
+		waitForClose(conn)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client())
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	var resErr GraphQLResponseError
+	assert.True(t, errors.As(msg.Err, &resErr))
+	assert.Equal(t, []GraphQLError{{Message: "Unknown rocket"}}, resErr.Errors)
+
+	_, open := <-msgs
+	assert.False(t, open)
+}
+
+func TestClient_Subscribe_ContextCancel(t *testing.T) {
+	t.Parallel()
+	completed := make(chan wsMessage, 1)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebSocket(t, w, r)
+		defer conn.close()
+		ackSubscription(t, conn)
+
+		msg, err := conn.readJSON()
+		assert.NoError(t, err)
+		assert.NoError(t, conn.writeJSON(wsMessage{ID: msg.ID, Type: msgNext, Payload: json.RawMessage(`{"data":{}}`)}))
+
+		msg, err = conn.readJSON()
+		assert.NoError(t, err)
+		completed <- msg
+	}))
+	defer svr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := New(svr.URL, svr.Client())
+	msgs, err := client.Subscribe(ctx, "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	assert.NoError(t, msg.Err)
+	cancel()
+
+	for range msgs {
+	}
+	assert.Equal(t, wsMessage{ID: subscriptionID, Type: msgComplete}, <-completed)
+}
+
+func TestClient_Subscribe_MessageTooLarge(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebSocket(t, w, r)
+		defer conn.close()
+		ackSubscription(t, conn)
+
+		_, err := conn.readJSON()
+		assert.NoError(t, err)
+		// Text frame header with 64-bit extended payload length of 2^62 bytes.
+		header := []byte{0x81, 127, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint64(header[2:], 1<<62)
+		_, err = conn.conn.Write(header)
+		assert.NoError(t, err)
+		waitForClose(conn)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client())
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	expErr := GraphQLCallError{
+		Message: "GraphQL subscription failed",
+		Reason:  "WebSocket message exceeds maximum size of 33554432 bytes",
+	}
+	assert.ErrorIs(t, msg.Err, expErr)
+
+	_, open := <-msgs
+	assert.False(t, open)
+}
+
+func TestClient_Subscribe_MaxMessageSize(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebSocket(t, w, r)
+		defer conn.close()
+		ackSubscription(t, conn)
+
+		msg, err := conn.readJSON()
+		assert.NoError(t, err)
+		assert.NoError(t, conn.writeJSON(wsMessage{ID: msg.ID, Type: msgNext, Payload: json.RawMessage(`{"data":{"launched":{"name":"Falcon 9"}}}`)}))
+		waitForClose(conn)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client(), WithMaxMessageSize(64))
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+	assert.NoError(t, err)
+
+	msg := <-msgs
+	expErr := GraphQLCallError{
+		Message: "GraphQL subscription failed",
+		Reason:  "WebSocket message exceeds maximum size of 64 bytes",
+	}
+	assert.ErrorIs(t, msg.Err, expErr)
+}
+
+func TestClient_Subscribe_Handshake_Error(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer svr.Close()
+
+	client := New(svr.URL, svr.Client())
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+
+	assert.Nil(t, msgs)
+	expErr := GraphQLCallError{
+		Message: "Opening GraphQL subscription failed",
+		Reason:  "unexpected HTTP status 400 of WebSocket handshake",
+	}
+	assert.ErrorIs(t, err, expErr)
+}
+
+func TestClient_Subscribe_Unsupported_Scheme(t *testing.T) {
+	t.Parallel()
+	client := New("smtp://localhost:8000", http.DefaultClient)
+	msgs, err := client.Subscribe(context.TODO(), "subscription { launched { name } }", nil, nil)
+
+	assert.Nil(t, msgs)
+	expErr := GraphQLCallError{
+		Message: "Opening GraphQL subscription failed",
+		Reason:  `unsupported WebSocket scheme "smtp"`,
+	}
+	assert.ErrorIs(t, err, expErr)
+}
+
+// acceptWebSocket is a test stand-in of WebSocket server - it accepts the handshake and hijacks the connection.
+func acceptWebSocket(t *testing.T, w http.ResponseWriter, r *http.Request) *wsConn {
+	assert.Equal(t, "websocket", r.Header.Get("Upgrade"))
+	assert.Equal(t, SubscriptionProtocol, r.Header.Get("Sec-WebSocket-Protocol"))
+
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	assert.NoError(t, err)
+
+	res := http.Response{
+		StatusCode: http.StatusSwitchingProtocols,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Upgrade":                {"websocket"},
+			"Connection":             {"Upgrade"},
+			"Sec-Websocket-Accept":   {wsAcceptKey(r.Header.Get("Sec-WebSocket-Key"))},
+			"Sec-Websocket-Protocol": {SubscriptionProtocol},
+		},
+	}
+	assert.NoError(t, res.Write(conn))
+
+	return &wsConn{
+		conn:   conn,
+		reader: bufio.NewReader(rw),
+	}
+}
+
+// ackSubscription reads connection_init message and acknowledges the connection.
+func ackSubscription(t *testing.T, conn *wsConn) {
+	msg, err := conn.readJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, msgConnectionInit, msg.Type)
+	assert.NoError(t, conn.writeJSON(wsMessage{Type: msgConnectionAck}))
+}
+
+// waitForClose reads messages until the client closes the connection.
+func waitForClose(conn *wsConn) {
+	for {
+		if _, _, err := conn.readMessage(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Package client contains the code used internally by grafik to prepare & send HTTP requests.
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes as per RFC 6455: https://datatracker.ietf.org/doc/html/rfc6455#section-5.2
+const (
+	wsOpContinuation byte = 0x0
+	wsOpText         byte = 0x1
+	wsOpBinary       byte = 0x2
+	wsOpClose        byte = 0x8
+	wsOpPing         byte = 0x9
+	wsOpPong         byte = 0xA
+)
+
+// wsAcceptGUID is a magic value used to compute Sec-WebSocket-Accept header.
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsCloseNormal is a close status code sent when the connection is closed by grafik.
+const wsCloseNormal = 1000
+
+// wsConn is a minimal WebSocket connection supporting text messages and control frames.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	// masked specifies if outgoing frames are masked. Frames sent by the client must be masked.
+	masked bool
+	// maxMessageSize limits the size of received message (joined fragmented frames). Zero means no limit.
+	maxMessageSize int64
+	// writeMu guards writes as messages are sent from multiple goroutines.
+	writeMu sync.Mutex
+	// closeOnce guards closing of the underlying connection.
+	closeOnce sync.Once
+}
+
+// dialWebSocket opens WebSocket connection to the endpoint and negotiates provided sub-protocol.
+// HTTP(S) endpoint is converted to WS(S) endpoint.
+func dialWebSocket(ctx context.Context, endpoint string, header http.Header, protocol string, tlsConfig *tls.Config) (*wsConn, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ws", "http":
+		u.Scheme = "http"
+	case "wss", "https":
+		u.Scheme = "https"
+		secure = true
+	default:
+		return nil, fmt.Errorf("unsupported WebSocket scheme %q", u.Scheme)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		if secure {
+			addr = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var conn net.Conn
+	if secure {
+		cfg := &tls.Config{}
+		if tlsConfig != nil {
+			cfg = tlsConfig.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		dialer := &tls.Dialer{Config: cfg}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := handshake(ctx, conn, u, header, protocol)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// handshake sends WebSocket opening handshake over conn and validates the response of the server.
+func handshake(ctx context.Context, conn net.Conn, u *url.URL, header http.Header, protocol string) (*wsConn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() {
+			_ = conn.SetDeadline(time.Time{})
+		}()
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		req.Header = header.Clone()
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Protocol", protocol)
+
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, err
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("unexpected HTTP status %d of WebSocket handshake", res.StatusCode)
+	}
+	if res.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		return nil, errors.New("invalid Sec-WebSocket-Accept header of WebSocket handshake")
+	}
+	if res.Header.Get("Sec-WebSocket-Protocol") != protocol {
+		return nil, fmt.Errorf("server does not support %q WebSocket sub-protocol", protocol)
+	}
+
+	return &wsConn{
+		conn:   conn,
+		reader: reader,
+		masked: true,
+	}, nil
+}
+
+// wsAcceptKey computes value of Sec-WebSocket-Accept header for given Sec-WebSocket-Key.
+func wsAcceptKey(key string) string {
+	h := sha1.New()
+	_, _ = io.WriteString(h, key+wsAcceptGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// writeMessage writes single, not fragmented frame.
+func (c *wsConn) writeMessage(opcode byte, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode
+	var maskBit byte
+	if c.masked {
+		maskBit = 0x80
+	}
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header[1] = maskBit | byte(length)
+	case length <= 0xFFFF:
+		header[1] = maskBit | 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = maskBit | 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	frame := make([]byte, len(payload))
+	copy(frame, payload)
+	if c.masked {
+		maskKey := make([]byte, 4)
+		if _, err := rand.Read(maskKey); err != nil {
+			return err
+		}
+		header = append(header, maskKey...)
+		maskBytes(maskKey, frame)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// readMessage reads next text or binary message joining fragmented frames.
+// Ping frames are answered with pong. Close frame is answered with close frame and results in io.EOF.
+func (c *wsConn) readMessage() (byte, []byte, error) {
+	var opcode byte
+	var message []byte
+	for {
+		fin, frameOpcode, payload, err := c.readFrame(len(message))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOpcode {
+		case wsOpPing:
+			if err := c.writeMessage(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			_ = c.writeMessage(wsOpClose, payload)
+			return 0, nil, io.EOF
+		case wsOpContinuation:
+			if opcode == 0 {
+				return 0, nil, errors.New("unexpected WebSocket continuation frame")
+			}
+		case wsOpText, wsOpBinary:
+			opcode = frameOpcode
+		default:
+			return 0, nil, fmt.Errorf("unsupported WebSocket opcode %d", frameOpcode)
+		}
+
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame reads single WebSocket frame and unmasks its payload.
+// buffered is the size of the message read from previous fragments - the frame must not make the message exceed maxMessageSize.
+func (c *wsConn) readFrame(buffered int) (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+	// Control frames can be sent between fragments of a message and are not part of it.
+	if opcode&0x8 != 0 {
+		buffered = 0
+	}
+	if c.maxMessageSize > 0 && length > uint64(c.maxMessageSize)-uint64(buffered) {
+		return false, 0, nil, fmt.Errorf("WebSocket message exceeds maximum size of %d bytes", c.maxMessageSize)
+	}
+
+	var maskKey []byte
+	if masked {
+		maskKey = make([]byte, 4)
+		if _, err := io.ReadFull(c.reader, maskKey); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		maskBytes(maskKey, payload)
+	}
+	return fin, opcode, payload, nil
+}
+
+// close sends close frame with normal closure status and closes the underlying connection.
+func (c *wsConn) close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		payload := make([]byte, 2)
+		binary.BigEndian.PutUint16(payload, wsCloseNormal)
+		_ = c.writeMessage(wsOpClose, payload)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// maskBytes applies WebSocket masking key to b in place.
+func maskBytes(key []byte, b []byte) {
+	for i := range b {
+		b[i] ^= key[i%4]
+	}
+}
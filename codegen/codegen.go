@@ -0,0 +1,210 @@
+// Package codegen provides Go API for generating grafik clients programmatically - i.e. from custom build tools and go:generate wrappers.
+// It is the same generation used by grafikgen CLI, but GraphQL schema and queries are provided as strings or readers and generated files are returned instead of being written to the disk.
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/evaluator"
+	"github.com/Bartosz-D3V/grafik/plugin"
+	"github.com/vektah/gqlparser"
+	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/gqlerror"
+	"github.com/vektah/gqlparser/parser"
+	"github.com/vektah/gqlparser/validator"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Source is single GraphQL schema or query document.
+// Content is read from Reader if it is not nil, otherwise Input is used. Name is used in errors and diagnostics - i.e. file name of the document.
+type Source struct {
+	Name   string
+	Input  string
+	Reader io.Reader
+}
+
+// Config describes grafik client to generate. Options correspond to grafikgen CLI flags.
+type Config struct {
+	// Schema is the GraphQL schema split into any number of SDL documents. JSON introspection result can be converted to SDL using introspection package.
+	Schema []Source
+	// Query contains GraphQL operations and fragments split into any number of documents. Fragments can be used by operations defined in other documents.
+	Query []Source
+	// PackageName is the name of the package of generated Go code.
+	PackageName string
+	// ClientName is the name of generated grafik client interface.
+	ClientName string
+	// FileName is the name of generated Go file; defaults to ClientName with .go extension. Persisted operations manifest is placed in the same directory.
+	FileName string
+	// UsePointers generates public GraphQL structs' fields as pointers.
+	UsePointers bool
+	// NullablePointers generates only nullable fields (and nullable list elements) as pointers. Cannot be used with UsePointers.
+	NullablePointers bool
+	// PerOperationTypes generates selection-exact response types for each operation instead of types shared between operations.
+	PerOperationTypes bool
+	// OptionalInputs generates nullable fields of input types as optional - omitted when nil or sent as explicit null when listed in NullFields.
+	OptionalInputs bool
+	// DiscriminatedUnions generates GraphQL interfaces and unions as Go interfaces with concrete struct per possible type decoded based on __typename.
+	DiscriminatedUnions bool
+	// FragmentStructs generates struct of each named fragment embedded in all structs spreading the fragment.
+	FragmentStructs bool
+	// PersistedQueries generates precomputed hashes of operations and executes them as automatic persisted queries.
+	PersistedQueries bool
+	// PersistedManifest generates persisted operations manifest (Apollo persisted query manifest format) next to generated client.
+	PersistedManifest bool
+	// ScalarBindings maps custom GraphQL scalars to Go types in the form of [import/path.]TypeName - i.e. "DateTime": "time.Time".
+	ScalarBindings map[string]string
+	// TemplateDir is the directory with custom templates overriding embedded templates of the same name - i.e. struct.tmpl.
+	TemplateDir string
+	// Plugins modify intermediate model of the client (types, enums and operations) before it is rendered - applied in the given order.
+	Plugins []plugin.Plugin
+}
+
+// GeneratedFile is single file generated by grafik - either formatted Go client or persisted operations manifest.
+type GeneratedFile struct {
+	Name    string
+	Content []byte
+}
+
+// Generate generates grafik client (and persisted operations manifest if requested) described by the config.
+// Problems found in the generated GraphQL definitions are returned as evaluator.Diagnostics.
+func Generate(ctx context.Context, cfg Config) ([]GeneratedFile, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	schemaSources, err := readSources(ctx, cfg.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content of GraphQL schema. Cause: %w", err)
+	}
+	schema, gqlErr := gqlparser.LoadSchema(schemaSources...)
+	if gqlErr != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema file. Cause: %w", gqlErr)
+	}
+
+	querySources, err := readSources(ctx, cfg.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content of GraphQL query file. Cause: %w", err)
+	}
+	query, errs := LoadQuery(schema, querySources)
+	if errs != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL query file. Cause: %s", strings.TrimSpace(errs.Error()))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	e := evaluator.New(schema, query, evaluator.AdditionalInfo{
+		PackageName:         cfg.PackageName,
+		ClientName:          cfg.ClientName,
+		UsePointers:         cfg.UsePointers,
+		NullablePointers:    cfg.NullablePointers,
+		PerOperationTypes:   cfg.PerOperationTypes,
+		OptionalInputs:      cfg.OptionalInputs,
+		DiscriminatedUnions: cfg.DiscriminatedUnions,
+		FragmentStructs:     cfg.FragmentStructs,
+		PersistedQueries:    cfg.PersistedQueries,
+		ScalarBindings:      cfg.ScalarBindings,
+		TemplateDir:         cfg.TemplateDir,
+		Plugins:             cfg.Plugins,
+	})
+
+	fileName := cfg.FileName
+	if fileName == "" {
+		fileName = fmt.Sprintf("%s.go", cfg.ClientName)
+	}
+	content, err := toBytes(e.Generate())
+	if err != nil {
+		return nil, err
+	}
+	files := []GeneratedFile{{Name: fileName, Content: content}}
+	if cfg.PersistedManifest {
+		content, err := toBytes(e.GenerateManifest())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{Name: getManifestDestName(fileName), Content: content})
+	}
+	return files, nil
+}
+
+// LoadQuery parses all GraphQL query sources into single query document and validates it against the schema.
+// Fragments can be used by operations defined in other sources.
+func LoadQuery(schema *ast.Schema, sources []*ast.Source) (*ast.QueryDocument, gqlerror.List) {
+	query := &ast.QueryDocument{}
+	for _, src := range sources {
+		doc, err := parser.ParseQuery(src)
+		if err != nil {
+			return nil, gqlerror.List{err}
+		}
+		query.Operations = append(query.Operations, doc.Operations...)
+		query.Fragments = append(query.Fragments, doc.Fragments...)
+	}
+	if errs := validator.Validate(schema, query); errs != nil {
+		return nil, errs
+	}
+	return query, nil
+}
+
+// validate checks if the combination of config options is correct.
+func (c Config) validate() error {
+	if len(c.Schema) == 0 || len(c.Query) == 0 {
+		return errors.New("at least one schema and query source is required")
+	}
+	if c.PackageName == "" || c.ClientName == "" {
+		return errors.New("package name and client name are required")
+	}
+	if c.UsePointers && c.NullablePointers {
+		return errors.New("use pointers and nullable pointers options are mutually exclusive")
+	}
+	if c.PersistedManifest && !c.PersistedQueries {
+		return errors.New("persisted manifest option requires persisted queries option")
+	}
+	for name, goType := range c.ScalarBindings {
+		if _, err := evaluator.ParseGoTypeRef(goType); err != nil {
+			return fmt.Errorf("invalid binding of GraphQL scalar %s. Cause: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// getManifestDestName returns file name of persisted operations manifest - in the same folder as generated client.
+func getManifestDestName(clientFileName string) string {
+	return filepath.Join(filepath.Dir(clientFileName), evaluator.ManifestFileName)
+}
+
+// readSources reads content of all sources.
+func readSources(ctx context.Context, sources []Source) ([]*ast.Source, error) {
+	astSources := make([]*ast.Source, len(sources))
+	for i, src := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		input := src.Input
+		if src.Reader != nil {
+			content, err := ioutil.ReadAll(src.Reader)
+			if err != nil {
+				return nil, err
+			}
+			input = string(content)
+		}
+		astSources[i] = &ast.Source{Name: src.Name, Input: input}
+	}
+	return astSources, nil
+}
+
+// toBytes returns generated content as bytes.
+func toBytes(content io.WriterTo, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := content.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write content of the grafik client. Cause: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,226 @@
+package codegen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/evaluator"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser"
+	"github.com/vektah/gqlparser/ast"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const schemaSDL = `type Query {
+    rockets(limit: Int): [Rocket]
+}
+
+type Rocket {
+    id: ID
+    name: String
+}`
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("../test/multi_file/query/rockets.graphql")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	files, err := Generate(context.Background(), Config{
+		Schema: []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query: []Source{
+			{Name: "rockets.graphql", Reader: f},
+			{Name: "fragments.graphql", Input: "fragment RocketInfo on Rocket {\n    id\n    name\n}"},
+		},
+		PackageName:       "space_x",
+		ClientName:        "SpaceXClient",
+		FileName:          "client/space_x.go",
+		PersistedQueries:  true,
+		PersistedManifest: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	assert.Equal(t, "client/space_x.go", files[0].Name)
+	assert.True(t, strings.HasPrefix(string(files[0].Content), "// Generated with grafik. DO NOT EDIT\n"))
+	assert.Contains(t, string(files[0].Content), "\npackage space_x\n")
+	assert.Contains(t, string(files[0].Content), "\ntype SpaceXClient interface {\n")
+	assert.Contains(t, string(files[0].Content), "fragment RocketInfo on Rocket")
+
+	assert.Equal(t, "client/persisted-query-manifest.json", files[1].Name)
+	assert.Contains(t, string(files[1].Content), `"name": "getRockets"`)
+}
+
+func TestGenerate_DefaultFileName(t *testing.T) {
+	t.Parallel()
+	files, err := Generate(context.Background(), Config{
+		Schema:      []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query:       []Source{{Name: "query.graphql", Input: "query getRockets {\n    rockets {\n        id\n    }\n}"}},
+		PackageName: "space_x",
+		ClientName:  "SpaceXClient",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "SpaceXClient.go", files[0].Name)
+}
+
+func TestGenerate_TemplateDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tmpl := "// New{{.ClientName}} creates {{.ClientName}} using default HTTP client.\nfunc New{{.ClientName}}(endpoint string) {{title .ClientName}} {\n    return &{{sentenceCase .ClientName}}{ctrl: GraphqlClient.New(endpoint, http.DefaultClient)}\n}"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "constructor.tmpl"), []byte(tmpl), 0600))
+
+	files, err := Generate(context.Background(), Config{
+		Schema:      []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query:       []Source{{Name: "query.graphql", Input: "query getRockets {\n    rockets {\n        id\n    }\n}"}},
+		PackageName: "space_x",
+		ClientName:  "SpaceXClient",
+		TemplateDir: dir,
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, string(files[0].Content), "// NewSpaceXClient creates SpaceXClient using default HTTP client.\nfunc NewSpaceXClient(endpoint string) SpaceXClient {\n")
+	assert.NotContains(t, string(files[0].Content), "func New(")
+
+	_, err = Generate(context.Background(), Config{
+		Schema:      []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query:       []Source{{Name: "query.graphql", Input: "query getRockets {\n    rockets {\n        id\n    }\n}"}},
+		PackageName: "space_x",
+		ClientName:  "SpaceXClient",
+		TemplateDir: filepath.Join(dir, "missing"),
+	})
+	assert.EqualError(t, err, fmt.Sprintf("failed to parse custom templates. Cause: no *.tmpl files found in %s", filepath.Join(dir, "missing")))
+}
+
+func TestGenerate_Error(t *testing.T) {
+	t.Parallel()
+	valid := Config{
+		Schema:      []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query:       []Source{{Name: "query.graphql", Input: "query getRockets {\n    rockets {\n        id\n    }\n}"}},
+		PackageName: "space_x",
+		ClientName:  "SpaceXClient",
+	}
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		expErr string
+	}{
+		{
+			"No sources",
+			func(c *Config) { c.Query = nil },
+			"at least one schema and query source is required",
+		},
+		{
+			"No client name",
+			func(c *Config) { c.ClientName = "" },
+			"package name and client name are required",
+		},
+		{
+			"Exclusive pointers",
+			func(c *Config) { c.UsePointers, c.NullablePointers = true, true },
+			"use pointers and nullable pointers options are mutually exclusive",
+		},
+		{
+			"Manifest without persisted queries",
+			func(c *Config) { c.PersistedManifest = true },
+			"persisted manifest option requires persisted queries option",
+		},
+		{
+			"Invalid scalar binding",
+			func(c *Config) { c.ScalarBindings = map[string]string{"DateTime": "time."} },
+			"invalid binding of GraphQL scalar DateTime. Cause: invalid Go type \"time.\" - expected [import/path.]TypeName",
+		},
+		{
+			"Invalid schema",
+			func(c *Config) {
+				c.Schema = []Source{{Name: "schema.graphql", Input: "type Query {\n    rockets: [Rocket]\n}"}}
+			},
+			"failed to parse GraphQL schema file. Cause: schema.graphql:2: Undefined type Rocket.",
+		},
+		{
+			"Invalid query",
+			func(c *Config) {
+				c.Query = []Source{{Name: "query.graphql", Input: "query getRockets {\n    rockets {\n        mass\n    }\n}"}}
+			},
+			"failed to parse GraphQL query file. Cause: query.graphql:3: Cannot query field \"mass\" on type \"Rocket\".",
+		},
+	}
+
+	for _, test := range tests {
+		cfg := valid
+		test.modify(&cfg)
+		_, err := Generate(context.Background(), cfg)
+		assert.EqualError(t, err, test.expErr, test.name)
+	}
+}
+
+func TestGenerate_Diagnostics(t *testing.T) {
+	t.Parallel()
+	_, err := Generate(context.Background(), Config{
+		Schema:      []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query:       []Source{{Name: "query.graphql", Input: "query getRockets {\n    ...RocketsInfo\n}\n\nfragment RocketsInfo on Query {\n    rockets {\n        id\n    }\n}"}},
+		PackageName: "space_x",
+		ClientName:  "SpaceXClient",
+	})
+
+	var diagnostics evaluator.Diagnostics
+	assert.True(t, errors.As(err, &diagnostics))
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, 2, diagnostics[0].Position.Line)
+	assert.EqualError(t, err, "query.graphql:2:8: fragments selected directly in the operation are supported only with per operation types")
+}
+
+func TestGenerate_Canceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Generate(ctx, Config{
+		Schema:      []Source{{Name: "schema.graphql", Input: schemaSDL}},
+		Query:       []Source{{Name: "query.graphql", Input: "query getRockets {\n    rockets {\n        id\n    }\n}"}},
+		PackageName: "space_x",
+		ClientName:  "SpaceXClient",
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestLoadQuery(t *testing.T) {
+	t.Parallel()
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSDL})
+	assert.Nil(t, gqlErr)
+
+	query, errs := LoadQuery(schema, []*ast.Source{
+		{Name: "rockets.graphql", Input: "query getRockets {\n    rockets {\n        ...RocketInfo\n    }\n}"},
+		{Name: "fragments.graphql", Input: "fragment RocketInfo on Rocket {\n    id\n}"},
+	})
+	assert.Nil(t, errs)
+	assert.Len(t, query.Operations, 1)
+	assert.Len(t, query.Fragments, 1)
+	assert.Equal(t, "rockets.graphql", query.Operations[0].Position.Src.Name)
+	assert.Equal(t, "fragments.graphql", query.Fragments[0].Position.Src.Name)
+
+	_, errs = LoadQuery(schema, []*ast.Source{
+		{Name: "rockets.graphql", Input: "query getRockets {\n    rockets {\n        id\n    }\n}"},
+		{Name: "launches.graphql", Input: "query getLaunches {\n    launches {\n        date\n    }\n}"},
+	})
+	assert.EqualError(t, errs, "launches.graphql:2: Cannot query field \"launches\" on type \"Query\".\n")
+}
+
+func TestGetManifestDestName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		fileName string
+		exp      string
+	}{
+		{"my_client.go", "persisted-query-manifest.json"},
+		{"/dev/null/client/my_client.go", "/dev/null/client/persisted-query-manifest.json"},
+		{"/dev/null/client/MyClient.go", "/dev/null/client/persisted-query-manifest.json"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.exp, getManifestDestName(test.fileName))
+	}
+}
@@ -14,3 +14,12 @@ func IsComplex(astType *ast.Type) bool {
 		astType.NamedType != "ID" && astType.NamedType != "Float" &&
 		astType.NamedType != "Boolean"
 }
+
+// LeafType unwraps the type of list (single/multi dimensional).
+// If the type is a list (i.e. [[Character!]]) then return leaf type (in this example Character!).
+func LeafType(astType *ast.Type) *ast.Type {
+	if IsList(astType) {
+		return LeafType(astType.Elem)
+	}
+	return astType
+}
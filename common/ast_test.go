@@ -72,3 +72,30 @@ func TestIsComplex(t *testing.T) {
 		assert.Equal(t, IsComplex(test.val), test.exp)
 	}
 }
+
+func TestLeafType(t *testing.T) {
+	t.Parallel()
+
+	person := ast.NonNullNamedType("Person", &ast.Position{})
+	tests := []struct {
+		val *ast.Type
+		exp *ast.Type
+	}{
+		{
+			val: person,
+			exp: person,
+		},
+		{
+			val: ast.ListType(person, &ast.Position{}),
+			exp: person,
+		},
+		{
+			val: ast.NonNullListType(ast.ListType(person, &ast.Position{}), &ast.Position{}),
+			exp: person,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Same(t, test.exp, LeafType(test.val))
+	}
+}
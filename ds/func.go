@@ -13,11 +13,17 @@ import (
 // Type is a string and represents return type of the function - i.e. "string", "Address" etc.
 // WrapperTypes is a slice of TypeArg and represents selection set in GraphQL operation.
 // It is used to create wrapper struct containing all values in selection set.
+// Subscription specifies if the function represents GraphQL subscription.
+// Mutation specifies if the function represents GraphQL mutation.
+// Persisted specifies if the function executes the operation as automatic persisted query using precomputed hash.
 type Func struct {
 	Name         string
 	Args         []TypeArg
 	Type         string
 	WrapperTypes []TypeField
+	Subscription bool
+	Mutation     bool
+	Persisted    bool
 }
 
 // JoinArgsBy returns list of function arguments as concatenated string with name and type.
@@ -31,6 +37,15 @@ func (f Func) JoinArgsBy(s string) string {
 	return strings.Join(pArgs, s)
 }
 
+// JoinArgNamesBy returns list of function argument names as concatenated string.
+func (f Func) JoinArgNamesBy(s string) string {
+	pArgs := make([]string, len(f.Args))
+	for i, arg := range f.Args {
+		pArgs[i] = arg.Name
+	}
+	return strings.Join(pArgs, s)
+}
+
 // ExportName converts name of the function to Title case.
 func (f Func) ExportName() string {
 	return strings.Title(f.Name)
@@ -84,6 +84,45 @@ func TestFunc_JoinArgsBy(t *testing.T) {
 	}
 }
 
+func TestFunc_JoinArgNamesBy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		f   Func
+		exp string
+	}{
+		{Func{}, ""},
+		{
+			Func{
+				Args: []TypeArg{
+					{
+						Name: "age",
+						Type: "int",
+					},
+				},
+			},
+			"age",
+		},
+		{
+			Func{
+				Args: []TypeArg{
+					{
+						Name: "age",
+						Type: "int",
+					},
+					{
+						Name: "address",
+						Type: "Address_and_contact_information",
+					},
+				},
+			},
+			"age, address",
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.exp, test.f.JoinArgNamesBy(", "))
+	}
+}
+
 func TestFunc_ExportName(t *testing.T) {
 	t.Parallel()
 
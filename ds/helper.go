@@ -1,8 +1,23 @@
 // Package ds (Data Structure) contains all golang data structures used by generator.
 package ds
 
+import "strings"
+
 // isPrimitive checks if passed golang type is primitive.
 // It only checks golang primitives generated by evaluator.
 func isPrimitive(s string) bool {
-	return s == "string" || s == "int" || s == "bool"
+	return s == "string" || s == "int" || s == "float64" || s == "bool" || s == errorType
+}
+
+// errorType is a built-in golang error interface used by generated subscription events.
+const errorType = "error"
+
+// exportType converts golang type to TitleCase excluding golang primitive types.
+// Slice and pointer prefixes are preserved - i.e. "[]*person" -> "[]*Person".
+func exportType(s string) string {
+	elType := strings.TrimLeft(s, "[]*")
+	if isPrimitive(elType) {
+		return s
+	}
+	return s[:len(s)-len(elType)] + strings.Title(elType)
 }
@@ -0,0 +1,10 @@
+// Package ds (Data Structure) contains all golang data structures used by generator.
+package ds
+
+// Import represents simplified import spec in Golang AST.
+// Alias is the optional name of the imported package.
+// Path is the import path of the package - i.e. "net/http".
+type Import struct {
+	Alias string
+	Path  string
+}
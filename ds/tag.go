@@ -0,0 +1,9 @@
+// Package ds (Data Structure) contains all golang data structures used by generator.
+package ds
+
+// Tag represents single key of struct field tag in Golang AST - i.e. db:"name".
+// Key is the key of the tag and Value is its unquoted value.
+type Tag struct {
+	Key   string
+	Value string
+}
@@ -2,7 +2,6 @@
 package ds
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -21,23 +20,6 @@ func (t TypeArg) ExportName() string {
 
 // ExportType converts function argument type to TitleCase excluding golang primitive types.
 func (t TypeArg) ExportType() TypeArg {
-	const sliceTok = "[]"
-	if strings.Contains(t.Type, sliceTok) {
-		dim := strings.Count(t.Type, sliceTok)
-		elType := strings.TrimLeft(t.Type, sliceTok)
-		if isPrimitive(elType) {
-			return t
-		}
-		return TypeArg{
-			Name: t.Name,
-			Type: fmt.Sprintf("%s%s", strings.Repeat(sliceTok, dim), strings.Title(elType)),
-		}
-	}
-	if isPrimitive(t.Type) {
-		return t
-	}
-	return TypeArg{
-		Name: t.Name,
-		Type: strings.Title(t.Type),
-	}
+	t.Type = exportType(t.Type)
+	return t
 }
@@ -34,6 +34,8 @@ func TestTypeArg_ExportType(t *testing.T) {
 		{TypeArg{Type: "string"}, "string"},
 		{TypeArg{Type: "int"}, "int"},
 		{TypeArg{Type: "bool"}, "bool"},
+		{TypeArg{Type: "float64"}, "float64"},
+		{TypeArg{Type: "[]float64"}, "[]float64"},
 		{TypeArg{Type: "[]string"}, "[]string"},
 		{TypeArg{Type: "[]int"}, "[]int"},
 		{TypeArg{Type: "[]bool"}, "[]bool"},
@@ -49,6 +51,10 @@ func TestTypeArg_ExportType(t *testing.T) {
 		{TypeArg{Type: "[][][]person"}, "[][][]Person"},
 		{TypeArg{Type: "Person"}, "Person"},
 		{TypeArg{Type: "[]Person"}, "[]Person"},
+		{TypeArg{Type: "*string"}, "*string"},
+		{TypeArg{Type: "*person"}, "*Person"},
+		{TypeArg{Type: "[]*person"}, "[]*Person"},
+		{TypeArg{Type: "[][]*float64"}, "[][]*float64"},
 	}
 
 	for _, test := range tests {
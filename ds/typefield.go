@@ -10,10 +10,16 @@ import (
 // Name is the name of the field.
 // Type is type of the field defined as string - i.e. "string", "int", "Address" etc.
 // JsonName is the name of the field used in `json:` tag.
+// NonNull is true if GraphQL type of the field is non-null (i.e. String!).
+// Tags are additional struct tags of public struct field written after `json:` tag.
+// Embedded is true if the field is embedded struct of named GraphQL fragment - only Type is written and the field is omitted from JSON.
 type TypeField struct {
 	Name     string
 	Type     string
 	JsonName string
+	NonNull  bool
+	Tags     []Tag
+	Embedded bool
 }
 
 // ExportName converts field name to TitleCase.
@@ -23,37 +29,15 @@ func (t TypeField) ExportName() string {
 
 // ExportType converts field name to TitleCase excluding golang primitive types.
 func (t TypeField) ExportType() TypeField {
-	const sliceTok = "[]"
-	if strings.Contains(t.Type, sliceTok) {
-		dim := strings.Count(t.Type, sliceTok)
-		elType := strings.TrimLeft(t.Type, sliceTok)
-		if isPrimitive(elType) {
-			return t
-		}
-		return TypeField{
-			Name:     t.Name,
-			Type:     fmt.Sprintf("%s%s", strings.Repeat(sliceTok, dim), strings.Title(elType)),
-			JsonName: t.JsonName,
-		}
-	}
-	if isPrimitive(t.Type) {
-		return t
-	}
-	return TypeField{
-		Name:     t.Name,
-		Type:     strings.Title(t.Type),
-		JsonName: t.JsonName,
-	}
+	t.Type = exportType(t.Type)
+	return t
 }
 
-// PointerType converts TypeField to pointer type, excluding arrays/slices/maps.
+// PointerType converts TypeField to pointer type, excluding arrays/slices/maps, error interface and types that already are pointers.
 func (t TypeField) PointerType() TypeField {
-	if strings.Contains(t.Type, "[]") {
+	if strings.Contains(t.Type, "[]") || strings.HasPrefix(t.Type, "*") || t.Type == errorType {
 		return t
 	}
-	return TypeField{
-		Name:     t.Name,
-		Type:     fmt.Sprintf("*%s", t.Type),
-		JsonName: t.JsonName,
-	}
+	t.Type = fmt.Sprintf("*%s", t.Type)
+	return t
 }
@@ -34,6 +34,8 @@ func TestTypeField_ExportType(t *testing.T) {
 		{TypeField{Type: "string"}, "string"},
 		{TypeField{Type: "int"}, "int"},
 		{TypeField{Type: "bool"}, "bool"},
+		{TypeField{Type: "float64"}, "float64"},
+		{TypeField{Type: "[]float64"}, "[]float64"},
 		{TypeField{Type: "[]string"}, "[]string"},
 		{TypeField{Type: "[]int"}, "[]int"},
 		{TypeField{Type: "[]bool"}, "[]bool"},
@@ -49,6 +51,11 @@ func TestTypeField_ExportType(t *testing.T) {
 		{TypeField{Type: "[][][]person"}, "[][][]Person"},
 		{TypeField{Type: "Person"}, "Person"},
 		{TypeField{Type: "[]Person"}, "[]Person"},
+		{TypeField{Type: "*string"}, "*string"},
+		{TypeField{Type: "*person"}, "*Person"},
+		{TypeField{Type: "[]*person"}, "[]*Person"},
+		{TypeField{Type: "[][]*float64"}, "[][]*float64"},
+		{TypeField{Type: "error"}, "error"},
 	}
 
 	for _, test := range tests {
@@ -81,6 +88,9 @@ func TestTypeField_PointerType(t *testing.T) {
 		{TypeField{Type: "[][][]person"}, "[][][]person"},
 		{TypeField{Type: "Person"}, "*Person"},
 		{TypeField{Type: "[]Person"}, "[]Person"},
+		{TypeField{Type: "*Person"}, "*Person"},
+		{TypeField{Type: "[]*Person"}, "[]*Person"},
+		{TypeField{Type: "error"}, "error"},
 	}
 
 	for _, test := range tests {
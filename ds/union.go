@@ -0,0 +1,20 @@
+// Package ds (Data Structure) contains all golang data structures used by generator.
+package ds
+
+// Union represents Go interface implemented by concrete structs of all possible types of GraphQL interface or union.
+// Name is the name of the struct wrapping the interface - it decodes the value into the member matching __typename.
+// InterfaceName is the name of Go interface implemented by all members.
+// Members is a slice of UnionMember and represents concrete structs of all possible types.
+type Union struct {
+	Name          string
+	InterfaceName string
+	Members       []UnionMember
+}
+
+// UnionMember represents concrete struct of a possible type of GraphQL interface or union.
+// TypeName is the name of GraphQL object type - i.e. value of __typename.
+// StructName is the name of the struct the value is decoded into.
+type UnionMember struct {
+	TypeName   string
+	StructName string
+}
@@ -2,9 +2,29 @@
 // It orchestrates the generation of the code by using visitor & generator packages.
 package evaluator
 
+import "github.com/Bartosz-D3V/grafik/plugin"
+
 // AdditionalInfo stores all optional arguments passed to grafikgen through CLI as flags.
 type AdditionalInfo struct {
 	PackageName string
 	ClientName  string
 	UsePointers bool
+	// NullablePointers generates only nullable fields (and nullable list elements) as pointers.
+	NullablePointers bool
+	// OptionalInputs generates nullable fields of input types as optional - omitted when nil or sent as explicit null when listed in NullFields.
+	OptionalInputs bool
+	// PerOperationTypes generates selection-exact response types for each operation instead of types shared between operations.
+	PerOperationTypes bool
+	// DiscriminatedUnions generates GraphQL interfaces and unions as Go interfaces with concrete struct per possible type decoded based on __typename.
+	DiscriminatedUnions bool
+	// FragmentStructs generates struct of each named fragment embedded in all structs spreading the fragment.
+	FragmentStructs bool
+	// PersistedQueries generates precomputed hashes of operations and executes them as automatic persisted queries.
+	PersistedQueries bool
+	// ScalarBindings maps custom GraphQL scalars to Go types in the form of [import/path.]TypeName - i.e. "DateTime": "time.Time".
+	ScalarBindings map[string]string
+	// TemplateDir is the directory with custom templates overriding embedded templates of the generator of the same name - i.e. struct.tmpl.
+	TemplateDir string
+	// Plugins modify intermediate model of the client (types, enums and operations) before it is rendered - applied in the given order.
+	Plugins []plugin.Plugin
 }
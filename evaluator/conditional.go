@@ -0,0 +1,94 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"github.com/Bartosz-D3V/grafik/common"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/vektah/gqlparser/ast"
+)
+
+// isConditional returns true if selection with the directives is included in the response depending on @include or @skip directive.
+func isConditional(directives ast.DirectiveList) bool {
+	return directives.ForName("include") != nil || directives.ForName("skip") != nil
+}
+
+// optionalField returns the field as pointer if it is conditional, so the field not included in the response can be distinguished from zero value.
+// Slices are not converted - they are nil if the field is not included.
+func optionalField(field ds.TypeField, conditional bool) ds.TypeField {
+	if !conditional {
+		return field
+	}
+	return field.PointerType()
+}
+
+// typeSelectionSets returns selection sets of all fields of the GraphQL type selected by operations, including fields of used fragments.
+func (e *evaluator) typeSelectionSets(typeName string) []ast.SelectionSet {
+	sets := make([]ast.SelectionSet, 0)
+	for _, f := range e.selectedFields(func(f *ast.Field) bool {
+		return len(f.SelectionSet) > 0 && common.LeafType(f.Definition.Type).NamedType == typeName
+	}) {
+		sets = append(sets, f.SelectionSet)
+	}
+	return sets
+}
+
+// typeConditionalFields returns names of fields of the GraphQL type that may be omitted from the response in any of its selection sets because of @include or @skip directive.
+// Struct shared by all selection sets gets the field as pointer even if it is always included in other selection sets.
+func (e *evaluator) typeConditionalFields(typeName string, matches func(typeCondition string) bool) map[string]bool {
+	return conditionalFields(e.typeSelectionSets(typeName), matches, !e.AdditionalInfo.FragmentStructs)
+}
+
+// conditionalFields returns names of fields that may be omitted from the response in any of the selection sets because of @include or @skip directive.
+func conditionalFields(sets []ast.SelectionSet, matches func(typeCondition string) bool, flattenSpreads bool) map[string]bool {
+	conditional := make(map[string]bool)
+	for _, set := range sets {
+		for name := range conditionalNames(collectMatchingFields(set, make([]*selectedField, 0), matches, flattenSpreads)) {
+			conditional[name] = true
+		}
+	}
+	return conditional
+}
+
+// conditionalNames returns names of GraphQL fields of collected conditional fields.
+func conditionalNames(fields []*selectedField) map[string]bool {
+	names := make(map[string]bool)
+	for _, f := range fields {
+		if f.conditional {
+			names[f.field.Name] = true
+		}
+	}
+	return names
+}
+
+// conditionalFragments returns names of fragments spread with @include or @skip directive (or inside inline fragment with the directive).
+// Fragments spread by conditional fragments are conditional as well - all fields of their structs may be omitted from the response.
+func (e *evaluator) conditionalFragments() map[string]bool {
+	conditional := make(map[string]bool)
+	visited := make(map[string]bool)
+	var walk func(set ast.SelectionSet, setConditional bool)
+	walk = func(set ast.SelectionSet, setConditional bool) {
+		for _, sel := range set {
+			switch s := sel.(type) {
+			case *ast.Field:
+				walk(s.SelectionSet, false)
+			case *ast.InlineFragment:
+				walk(s.SelectionSet, setConditional || isConditional(s.Directives))
+			case *ast.FragmentSpread:
+				spreadConditional := setConditional || isConditional(s.Directives)
+				if s.Definition == nil || visited[s.Name] && (conditional[s.Name] || !spreadConditional) {
+					continue
+				}
+				visited[s.Name] = true
+				if spreadConditional {
+					conditional[s.Name] = true
+				}
+				walk(s.Definition.SelectionSet, spreadConditional)
+			}
+		}
+	}
+	for _, op := range e.queryDocument.Operations {
+		walk(op.SelectionSet, false)
+	}
+	return conditional
+}
@@ -0,0 +1,69 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"fmt"
+	"github.com/vektah/gqlparser/ast"
+	"strings"
+)
+
+// Diagnostic is a problem found during the generation of grafik client.
+// Position points to GraphQL definition (operation, fragment, field or schema type) that caused the problem - it is nil if the problem is not related to any definition.
+type Diagnostic struct {
+	Position *ast.Position
+	Err      error
+}
+
+// Error returns the problem prefixed with the source name, line and column of the definition - i.e. "query.graphql:3:5: failed to ...".
+func (d Diagnostic) Error() string {
+	if d.Position == nil {
+		return d.Err.Error()
+	}
+	srcName := "input"
+	if d.Position.Src != nil && d.Position.Src.Name != "" {
+		srcName = d.Position.Src.Name
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", srcName, d.Position.Line, d.Position.Column, d.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics is a list of all problems found during the generation of grafik client. It is returned as error by Evaluator.
+type Diagnostics []Diagnostic
+
+// Error returns all problems separated by new line.
+func (d Diagnostics) Error() string {
+	msgs := make([]string, len(d))
+	for i, diagnostic := range d {
+		msgs[i] = diagnostic.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// report records the problem at the position of currently generated GraphQL definition. Nil error is ignored.
+func (e *evaluator) report(err error) {
+	e.reportAt(e.position, err)
+}
+
+// reportAt records the problem at the given position. Nil error is ignored.
+func (e *evaluator) reportAt(pos *ast.Position, err error) {
+	if err == nil {
+		return
+	}
+	e.diagnostics = append(e.diagnostics, Diagnostic{
+		Position: pos,
+		Err:      err,
+	})
+}
+
+// err returns all recorded problems as error or nil if there are none.
+func (e *evaluator) err() error {
+	if len(e.diagnostics) == 0 {
+		return nil
+	}
+	return e.diagnostics
+}
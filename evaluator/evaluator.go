@@ -10,8 +10,10 @@ import (
 )
 
 // Evaluator provides a contract for evaluator and is being used as a return type of New instead of a pointer.
+// Problems found during the generation are returned as Diagnostics.
 type Evaluator interface {
-	Generate() io.WriterTo
+	Generate() (io.WriterTo, error)
+	GenerateManifest() (io.WriterTo, error)
 }
 
 // evaluator is a struct used internally by grafikgen to wrap all required services and properties.
@@ -22,13 +24,15 @@ type evaluator struct {
 	queryDocument              *ast.QueryDocument  // GraphQL query document provided via CLI.
 	AdditionalInfo             AdditionalInfo      // Additional info provided via CLI.
 	SpecialGraphqlTypesMapping map[string]string   // Special GraphQL types (i.e. __typename).
+	customTypes                map[string][]string // Custom GraphQL types used in query document with selected fields.
+	diagnostics                Diagnostics         // Problems found during the generation.
+	position                   *ast.Position       // Position of currently generated GraphQL definition.
+	model                      *modelBuilder       // Intermediate model modified by plugins - nil if no plugins are provided.
 }
 
 // New function creates an instance of evaluator.
 func New(schema *ast.Schema, queryDocument *ast.QueryDocument, additionalInfo AdditionalInfo) Evaluator {
-	g := generator.New()
 	return &evaluator{
-		generator:      g,
 		visitor:        visitor.New(schema, queryDocument),
 		schema:         schema,
 		queryDocument:  queryDocument,
@@ -40,24 +44,62 @@ func New(schema *ast.Schema, queryDocument *ast.QueryDocument, additionalInfo Ad
 }
 
 // Generate is a root level function that generates the whole grafik client.
-func (e *evaluator) Generate() io.WriterTo {
-	e.generator.WriteHeader()
-	e.generator.WriteLineBreak(twoLinesBreak)
+// If any plugins are provided, intermediate model of the client is collected first and modified by plugins before it is rendered.
+// All problems found during the generation are returned as Diagnostics.
+func (e *evaluator) Generate() (io.WriterTo, error) {
+	e.model = nil
+	if len(e.AdditionalInfo.Plugins) > 0 {
+		if err := e.collectModel(); err != nil {
+			return nil, err
+		}
+	}
+
+	g, err := generator.NewWithTemplateDir(e.AdditionalInfo.TemplateDir)
+	if err != nil {
+		return nil, Diagnostics{{Err: err}}
+	}
+	e.generator = g
+	e.render()
+
+	if err := e.err(); err != nil {
+		return nil, err
+	}
+	out, err := e.generator.Generate()
+	if err != nil {
+		return nil, Diagnostics{{Err: err}}
+	}
+	return out, nil
+}
+
+// render writes the whole grafik client using the generator.
+func (e *evaluator) render() {
+	e.diagnostics = nil
+	e.position = nil
+
+	e.customTypes = e.visitor.IntrospectTypes()
+	e.validateScalarBindings()
+
+	e.report(e.generator.WriteHeader())
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 
-	e.generator.WritePackage(e.AdditionalInfo.PackageName)
-	e.generator.WriteLineBreak(twoLinesBreak)
+	e.report(e.generator.WritePackage(e.AdditionalInfo.PackageName))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 
-	e.generator.WriteImports()
-	e.generator.WriteLineBreak(twoLinesBreak)
+	imports := append(e.operationImports(), e.scalarImports()...)
+	imports = append(imports, e.unionImports()...)
+	imports = append(imports, e.fragmentImports()...)
+	e.report(e.generator.WriteImports(imports...))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 
 	e.genSchemaDef()
-	e.generator.WriteLineBreak(oneLineBreak)
+	e.report(e.generator.WriteLineBreak(oneLineBreak))
 
 	e.genOperations()
-	e.generator.WriteLineBreak(oneLineBreak)
+	e.report(e.generator.WriteLineBreak(oneLineBreak))
 
 	e.genClientCode()
-	e.generator.WriteLineBreak(oneLineBreak)
+	e.report(e.generator.WriteLineBreak(oneLineBreak))
 
-	return e.generator.Generate()
+	e.genModelAdditions()
+	e.report(e.generator.WriteLineBreak(oneLineBreak))
 }
@@ -2,11 +2,21 @@ package evaluator
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/Bartosz-D3V/grafik/plugin"
 	"github.com/Bartosz-D3V/grafik/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/vektah/gqlparser"
 	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/parser"
+	"github.com/vektah/gqlparser/validator"
+	goast "go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
 	"io/ioutil"
 	"path"
 	"testing"
@@ -31,6 +41,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -39,21 +50,15 @@ type File struct {
 	Name string %[1]cjson:"name"%[1]c
 }
 
-const getFileNameWithId = %[1]cquery GetFileNameWithId($id: ID!) {
-    getFile(id: $id) {
-        name
-    }
-}%[1]c
+const getFileNameWithId = %[1]cquery GetFileNameWithId($id:ID!){getFile(id:$id){name}}%[1]c
 
-const renameFileWithId = %[1]cmutation RenameFileWithId($id: ID!, $name: String!) {
-    renameFile(id: $id, name: $name) {
-        name
-    }
-}%[1]c
+const renameFileWithId = %[1]cmutation RenameFileWithId($id:ID!$name:String!){renameFile(id:$id name:$name){name}}%[1]c
 
 type FilesClient interface {
 	GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error)
 	RenameFileWithId(ctx context.Context, id string, name string, header http.Header) (*http.Response, error)
+	RenameFileWithIdResponse(ctx context.Context, id string, name string, header http.Header) (*RenameFileWithIdResponse, error)
 }
 
 func (c *filesClient) GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error) {
@@ -63,6 +68,22 @@ func (c *filesClient) GetFileNameWithId(ctx context.Context, id string, header h
 	return c.ctrl.Execute(ctx, getFileNameWithId, params, header)
 }
 
+func (c *filesClient) GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error) {
+	res, err := c.GetFileNameWithId(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetFileNameWithIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (c *filesClient) RenameFileWithId(ctx context.Context, id string, name string, header http.Header) (*http.Response, error) {
 	params := make(map[string]interface{}, 2)
 	params["id"] = id
@@ -71,6 +92,22 @@ func (c *filesClient) RenameFileWithId(ctx context.Context, id string, name stri
 	return c.ctrl.Execute(ctx, renameFileWithId, params, header)
 }
 
+func (c *filesClient) RenameFileWithIdResponse(ctx context.Context, id string, name string, header http.Header) (*RenameFileWithIdResponse, error) {
+	res, err := c.RenameFileWithId(ctx, id, name, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out RenameFileWithIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetFileNameWithIdResponse struct {
 	Data   GetFileNameWithIdData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError        %[1]cjson:"errors"%[1]c
@@ -108,9 +145,9 @@ type filesClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) FilesClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) FilesClient {
 	return &filesClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -137,6 +174,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -149,16 +187,11 @@ type FilmsConnection struct {
 	Films []Film %[1]cjson:"films"%[1]c
 }
 
-const getAllFilmsProducers = %[1]cquery GetAllFilmsProducers {
-    allFilms {
-        films {
-            producers
-        }
-    }
-}%[1]c
+const getAllFilmsProducers = %[1]cquery GetAllFilmsProducers{allFilms{films{producers}}}%[1]c
 
 type FilmsClient interface {
 	GetAllFilmsProducers(ctx context.Context, header http.Header) (*http.Response, error)
+	GetAllFilmsProducersResponse(ctx context.Context, header http.Header) (*GetAllFilmsProducersResponse, error)
 }
 
 func (c *filmsClient) GetAllFilmsProducers(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -167,6 +200,22 @@ func (c *filmsClient) GetAllFilmsProducers(ctx context.Context, header http.Head
 	return c.ctrl.Execute(ctx, getAllFilmsProducers, params, header)
 }
 
+func (c *filmsClient) GetAllFilmsProducersResponse(ctx context.Context, header http.Header) (*GetAllFilmsProducersResponse, error) {
+	res, err := c.GetAllFilmsProducers(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetAllFilmsProducersResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetAllFilmsProducersResponse struct {
 	Data   GetAllFilmsProducersData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError           %[1]cjson:"errors"%[1]c
@@ -195,9 +244,9 @@ type filmsClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) FilmsClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) FilmsClient {
 	return &filmsClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -224,27 +273,22 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
 
 type Result struct {
-	X int %[1]cjson:"x"%[1]c
-	Y int %[1]cjson:"y"%[1]c
-	Z int %[1]cjson:"z"%[1]c
+	X float64 %[1]cjson:"x"%[1]c
+	Y float64 %[1]cjson:"y"%[1]c
+	Z float64 %[1]cjson:"z"%[1]c
 }
 
-const getAllResults = %[1]cquery GetAllResults {
-    allResults {
-        x,
-        y,
-        z
-    }
-    allResultsSimplified
-}%[1]c
+const getAllResults = %[1]cquery GetAllResults{allResults{x y z}allResultsSimplified}%[1]c
 
 type MathClient interface {
 	GetAllResults(ctx context.Context, header http.Header) (*http.Response, error)
+	GetAllResultsResponse(ctx context.Context, header http.Header) (*GetAllResultsResponse, error)
 }
 
 func (c *mathClient) GetAllResults(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -253,6 +297,22 @@ func (c *mathClient) GetAllResults(ctx context.Context, header http.Header) (*ht
 	return c.ctrl.Execute(ctx, getAllResults, params, header)
 }
 
+func (c *mathClient) GetAllResultsResponse(ctx context.Context, header http.Header) (*GetAllResultsResponse, error) {
+	res, err := c.GetAllResults(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetAllResultsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetAllResultsResponse struct {
 	Data   GetAllResultsData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -282,9 +342,9 @@ type mathClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) MathClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) MathClient {
 	return &mathClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -311,26 +371,22 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
 
 type Result struct {
-	X int %[1]cjson:"x"%[1]c
-	Y int %[1]cjson:"y"%[1]c
-	Z int %[1]cjson:"z"%[1]c
+	X float64 %[1]cjson:"x"%[1]c
+	Y float64 %[1]cjson:"y"%[1]c
+	Z float64 %[1]cjson:"z"%[1]c
 }
 
-const getAllResults = %[1]cquery GetAllResults {
-    allResults {
-        x,
-        y,
-        z
-    }
-}%[1]c
+const getAllResults = %[1]cquery GetAllResults{allResults{x y z}}%[1]c
 
 type MathClient interface {
 	GetAllResults(ctx context.Context, header http.Header) (*http.Response, error)
+	GetAllResultsResponse(ctx context.Context, header http.Header) (*GetAllResultsResponse, error)
 }
 
 func (c *mathClient) GetAllResults(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -339,6 +395,22 @@ func (c *mathClient) GetAllResults(ctx context.Context, header http.Header) (*ht
 	return c.ctrl.Execute(ctx, getAllResults, params, header)
 }
 
+func (c *mathClient) GetAllResultsResponse(ctx context.Context, header http.Header) (*GetAllResultsResponse, error) {
+	res, err := c.GetAllResults(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetAllResultsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetAllResultsResponse struct {
 	Data   GetAllResultsData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -367,9 +439,9 @@ type mathClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) MathClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) MathClient {
 	return &mathClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -396,6 +468,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -406,8 +479,8 @@ type Character struct {
 }
 
 type Location struct {
-	PosX int %[1]cjson:"posX"%[1]c
-	PoxY int %[1]cjson:"poxY"%[1]c
+	PosX float64 %[1]cjson:"posX"%[1]c
+	PoxY float64 %[1]cjson:"poxY"%[1]c
 }
 
 type Planet struct {
@@ -418,25 +491,11 @@ type Species struct {
 	Origin Planet %[1]cjson:"origin"%[1]c
 }
 
-const getHeroWithId123ABC = %[1]cquery GetHeroWithId123ABC {
-    getHero(characterSelector: {idSelector: {id: "123ABC"}}) {
-        homeWorld {
-            location {
-                posX
-            }
-        }
-        species {
-            origin {
-                location {
-                    poxY
-                }
-            }
-        }
-    }
-}%[1]c
+const getHeroWithId123ABC = %[1]cquery GetHeroWithId123ABC{getHero(characterSelector:{idSelector:{id:"123ABC"}}){homeWorld{location{posX}}species{origin{location{poxY}}}}}%[1]c
 
 type SpecificHeroClient interface {
 	GetHeroWithId123ABC(ctx context.Context, header http.Header) (*http.Response, error)
+	GetHeroWithId123ABCResponse(ctx context.Context, header http.Header) (*GetHeroWithId123ABCResponse, error)
 }
 
 func (c *specificHeroClient) GetHeroWithId123ABC(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -445,6 +504,22 @@ func (c *specificHeroClient) GetHeroWithId123ABC(ctx context.Context, header htt
 	return c.ctrl.Execute(ctx, getHeroWithId123ABC, params, header)
 }
 
+func (c *specificHeroClient) GetHeroWithId123ABCResponse(ctx context.Context, header http.Header) (*GetHeroWithId123ABCResponse, error) {
+	res, err := c.GetHeroWithId123ABC(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetHeroWithId123ABCResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetHeroWithId123ABCResponse struct {
 	Data   GetHeroWithId123ABCData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError          %[1]cjson:"errors"%[1]c
@@ -473,9 +548,9 @@ type specificHeroClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) SpecificHeroClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) SpecificHeroClient {
 	return &specificHeroClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -502,6 +577,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -519,14 +595,11 @@ const (
 	SUPPORT DepartmentName = "SUPPORT"
 )
 
-const getDepartment = %[1]cquery getDepartment {
-    getDepartment {
-        name
-    }
-}%[1]c
+const getDepartment = %[1]cquery getDepartment{getDepartment{name}}%[1]c
 
 type CompanyClient interface {
 	GetDepartment(ctx context.Context, header http.Header) (*http.Response, error)
+	GetDepartmentResponse(ctx context.Context, header http.Header) (*GetDepartmentResponse, error)
 }
 
 func (c *companyClient) GetDepartment(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -535,6 +608,22 @@ func (c *companyClient) GetDepartment(ctx context.Context, header http.Header) (
 	return c.ctrl.Execute(ctx, getDepartment, params, header)
 }
 
+func (c *companyClient) GetDepartmentResponse(ctx context.Context, header http.Header) (*GetDepartmentResponse, error) {
+	res, err := c.GetDepartment(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetDepartmentResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetDepartmentResponse struct {
 	Data   GetDepartmentData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -563,9 +652,9 @@ type companyClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CompanyClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CompanyClient {
 	return &companyClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -592,6 +681,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -605,15 +695,11 @@ type Date interface {
 
 }
 
-const getCapsulesByFullSelector = %[1]cquery GetCapsulesByFullSelector($order: String, $mission: String, $originalLaunch: Date, $id: ID, $sort: String) {
-    capsules(order: $order, find: {landings: 10, mission: $mission, original_launch: $originalLaunch, id: $id}, sort: $sort) {
-        id
-        type
-    }
-}%[1]c
+const getCapsulesByFullSelector = %[1]cquery GetCapsulesByFullSelector($order:String$mission:String$originalLaunch:Date$id:ID$sort:String){capsules(order:$order find:{landings:10 mission:$mission original_launch:$originalLaunch id:$id}sort:$sort){id type}}%[1]c
 
 type CapsulesClient interface {
 	GetCapsulesByFullSelector(ctx context.Context, order string, mission string, originalLaunch Date, id string, sort string, header http.Header) (*http.Response, error)
+	GetCapsulesByFullSelectorResponse(ctx context.Context, order string, mission string, originalLaunch Date, id string, sort string, header http.Header) (*GetCapsulesByFullSelectorResponse, error)
 }
 
 func (c *capsulesClient) GetCapsulesByFullSelector(ctx context.Context, order string, mission string, originalLaunch Date, id string, sort string, header http.Header) (*http.Response, error) {
@@ -627,6 +713,22 @@ func (c *capsulesClient) GetCapsulesByFullSelector(ctx context.Context, order st
 	return c.ctrl.Execute(ctx, getCapsulesByFullSelector, params, header)
 }
 
+func (c *capsulesClient) GetCapsulesByFullSelectorResponse(ctx context.Context, order string, mission string, originalLaunch Date, id string, sort string, header http.Header) (*GetCapsulesByFullSelectorResponse, error) {
+	res, err := c.GetCapsulesByFullSelector(ctx, order, mission, originalLaunch, id, sort, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCapsulesByFullSelectorResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCapsulesByFullSelectorResponse struct {
 	Data   GetCapsulesByFullSelectorData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError                %[1]cjson:"errors"%[1]c
@@ -655,9 +757,9 @@ type capsulesClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CapsulesClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CapsulesClient {
 	return &capsulesClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -684,6 +786,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -697,18 +800,15 @@ type Limit struct {
 }
 
 type Position struct {
-	X int %[1]cjson:"x"%[1]c
-	Y int %[1]cjson:"y"%[1]c
+	X float64 %[1]cjson:"x"%[1]c
+	Y float64 %[1]cjson:"y"%[1]c
 }
 
-const getCapsulesByPositions = %[1]cquery GetCapsulesByPositions($find: [[Position]], $limit: [[Limit]], $selector: [[String]]) {
-   capsules(find: $find, limit: $limit, selector: $selector) {
-       id
-   }
-}%[1]c
+const getCapsulesByPositions = %[1]cquery GetCapsulesByPositions($find:[[Position]]$limit:[[Limit]]$selector:[[String]]){capsules(find:$find limit:$limit selector:$selector){id}}%[1]c
 
 type CapsulesClient interface {
 	GetCapsulesByPositions(ctx context.Context, find [][]Position, limit [][]Limit, selector [][]string, header http.Header) (*http.Response, error)
+	GetCapsulesByPositionsResponse(ctx context.Context, find [][]Position, limit [][]Limit, selector [][]string, header http.Header) (*GetCapsulesByPositionsResponse, error)
 }
 
 func (c *capsulesClient) GetCapsulesByPositions(ctx context.Context, find [][]Position, limit [][]Limit, selector [][]string, header http.Header) (*http.Response, error) {
@@ -720,6 +820,22 @@ func (c *capsulesClient) GetCapsulesByPositions(ctx context.Context, find [][]Po
 	return c.ctrl.Execute(ctx, getCapsulesByPositions, params, header)
 }
 
+func (c *capsulesClient) GetCapsulesByPositionsResponse(ctx context.Context, find [][]Position, limit [][]Limit, selector [][]string, header http.Header) (*GetCapsulesByPositionsResponse, error) {
+	res, err := c.GetCapsulesByPositions(ctx, find, limit, selector, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCapsulesByPositionsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCapsulesByPositionsResponse struct {
 	Data   GetCapsulesByPositionsData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError             %[1]cjson:"errors"%[1]c
@@ -748,9 +864,9 @@ type capsulesClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CapsulesClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CapsulesClient {
 	return &capsulesClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -777,6 +893,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -790,18 +907,15 @@ type Limit struct {
 }
 
 type Position struct {
-	X int %[1]cjson:"x"%[1]c
-	Y int %[1]cjson:"y"%[1]c
+	X float64 %[1]cjson:"x"%[1]c
+	Y float64 %[1]cjson:"y"%[1]c
 }
 
-const getCapsulesByPositions = %[1]cquery GetCapsulesByPositions($find: [[[Position]]], $limit: [[[Limit]]], $selector: [[[String]]]) {
-   capsules(find: $find, limit: $limit, selector: $selector) {
-       id
-   }
-}%[1]c
+const getCapsulesByPositions = %[1]cquery GetCapsulesByPositions($find:[[[Position]]]$limit:[[[Limit]]]$selector:[[[String]]]){capsules(find:$find limit:$limit selector:$selector){id}}%[1]c
 
 type CapsulesClient interface {
 	GetCapsulesByPositions(ctx context.Context, find [][][]Position, limit [][][]Limit, selector [][][]string, header http.Header) (*http.Response, error)
+	GetCapsulesByPositionsResponse(ctx context.Context, find [][][]Position, limit [][][]Limit, selector [][][]string, header http.Header) (*GetCapsulesByPositionsResponse, error)
 }
 
 func (c *capsulesClient) GetCapsulesByPositions(ctx context.Context, find [][][]Position, limit [][][]Limit, selector [][][]string, header http.Header) (*http.Response, error) {
@@ -813,6 +927,22 @@ func (c *capsulesClient) GetCapsulesByPositions(ctx context.Context, find [][][]
 	return c.ctrl.Execute(ctx, getCapsulesByPositions, params, header)
 }
 
+func (c *capsulesClient) GetCapsulesByPositionsResponse(ctx context.Context, find [][][]Position, limit [][][]Limit, selector [][][]string, header http.Header) (*GetCapsulesByPositionsResponse, error) {
+	res, err := c.GetCapsulesByPositions(ctx, find, limit, selector, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCapsulesByPositionsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCapsulesByPositionsResponse struct {
 	Data   GetCapsulesByPositionsData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError             %[1]cjson:"errors"%[1]c
@@ -841,9 +971,9 @@ type capsulesClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CapsulesClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CapsulesClient {
 	return &capsulesClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -870,6 +1000,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -883,18 +1014,11 @@ type Movie struct {
 	Actor Actor  %[1]cjson:"actor"%[1]c
 }
 
-const getAllMoviesWhereActorsOfTheMovieActedIn = %[1]cquery GetAllMoviesWhereActorsOfTheMovieActedIn($title: String!) {
-    movie(title: $title) {
-        actor {
-            actedIn {
-                title
-            }
-        }
-    }
-}%[1]c
+const getAllMoviesWhereActorsOfTheMovieActedIn = %[1]cquery GetAllMoviesWhereActorsOfTheMovieActedIn($title:String!){movie(title:$title){actor{actedIn{title}}}}%[1]c
 
 type MovieClient interface {
 	GetAllMoviesWhereActorsOfTheMovieActedIn(ctx context.Context, title string, header http.Header) (*http.Response, error)
+	GetAllMoviesWhereActorsOfTheMovieActedInResponse(ctx context.Context, title string, header http.Header) (*GetAllMoviesWhereActorsOfTheMovieActedInResponse, error)
 }
 
 func (c *movieClient) GetAllMoviesWhereActorsOfTheMovieActedIn(ctx context.Context, title string, header http.Header) (*http.Response, error) {
@@ -904,6 +1028,22 @@ func (c *movieClient) GetAllMoviesWhereActorsOfTheMovieActedIn(ctx context.Conte
 	return c.ctrl.Execute(ctx, getAllMoviesWhereActorsOfTheMovieActedIn, params, header)
 }
 
+func (c *movieClient) GetAllMoviesWhereActorsOfTheMovieActedInResponse(ctx context.Context, title string, header http.Header) (*GetAllMoviesWhereActorsOfTheMovieActedInResponse, error) {
+	res, err := c.GetAllMoviesWhereActorsOfTheMovieActedIn(ctx, title, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetAllMoviesWhereActorsOfTheMovieActedInResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetAllMoviesWhereActorsOfTheMovieActedInResponse struct {
 	Data   GetAllMoviesWhereActorsOfTheMovieActedInData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError                               %[1]cjson:"errors"%[1]c
@@ -932,9 +1072,9 @@ type movieClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) MovieClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) MovieClient {
 	return &movieClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -961,6 +1101,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -973,29 +1114,11 @@ type Rocket struct {
 	Name        string %[1]cjson:"name"%[1]c
 }
 
-const getShortRocketInfo = %[1]cquery GetShortRocketInfo {
-    rockets {
-        ...RocketShortInfo
-    }
-}
-fragment RocketShortInfo on Rocket {
-    id
-    name
-    description
-    ...AdditionalRocketInfo
-}
-fragment AdditionalRocketInfo on Rocket {
-    country
-    ... on Rocket {
-        ...InformatoryRocketInfo
-    }
-}
-fragment InformatoryRocketInfo on Rocket {
-    active
-}%[1]c
+const getShortRocketInfo = %[1]cquery GetShortRocketInfo{rockets{...RocketShortInfo}}fragment RocketShortInfo on Rocket{id name description...AdditionalRocketInfo}fragment AdditionalRocketInfo on Rocket{country...on Rocket{...InformatoryRocketInfo}}fragment InformatoryRocketInfo on Rocket{active}%[1]c
 
 type RocketClient interface {
 	GetShortRocketInfo(ctx context.Context, header http.Header) (*http.Response, error)
+	GetShortRocketInfoResponse(ctx context.Context, header http.Header) (*GetShortRocketInfoResponse, error)
 }
 
 func (c *rocketClient) GetShortRocketInfo(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1004,6 +1127,22 @@ func (c *rocketClient) GetShortRocketInfo(ctx context.Context, header http.Heade
 	return c.ctrl.Execute(ctx, getShortRocketInfo, params, header)
 }
 
+func (c *rocketClient) GetShortRocketInfoResponse(ctx context.Context, header http.Header) (*GetShortRocketInfoResponse, error) {
+	res, err := c.GetShortRocketInfo(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetShortRocketInfoResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetShortRocketInfoResponse struct {
 	Data   GetShortRocketInfoData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError         %[1]cjson:"errors"%[1]c
@@ -1032,9 +1171,9 @@ type rocketClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) RocketClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
 	return &rocketClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1061,6 +1200,7 @@ package grafik_client
 
 import (
     "context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1075,19 +1215,11 @@ type Country struct {
 	Name string %[1]cjson:"name"%[1]c
 }
 
-const getCountriesAndContinents = %[1]cquery getCountriesAndContinents {
-    continents {
-        code
-        name
-    }
-    countries {
-        code
-        name
-    }
-}%[1]c
+const getCountriesAndContinents = %[1]cquery getCountriesAndContinents{continents{code name}countries{code name}}%[1]c
 
 type CountriesClient interface {
 	GetCountriesAndContinents(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCountriesAndContinentsResponse(ctx context.Context, header http.Header) (*GetCountriesAndContinentsResponse, error)
 }
 
 func (c *countriesClient) GetCountriesAndContinents(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1096,6 +1228,22 @@ func (c *countriesClient) GetCountriesAndContinents(ctx context.Context, header
 	return c.ctrl.Execute(ctx, getCountriesAndContinents, params, header)
 }
 
+func (c *countriesClient) GetCountriesAndContinentsResponse(ctx context.Context, header http.Header) (*GetCountriesAndContinentsResponse, error) {
+	res, err := c.GetCountriesAndContinents(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCountriesAndContinentsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCountriesAndContinentsResponse struct {
 	Data   GetCountriesAndContinentsData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError                %[1]cjson:"errors"%[1]c
@@ -1125,9 +1273,9 @@ type countriesClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CountriesClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CountriesClient {
 	return &countriesClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1154,6 +1302,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1164,24 +1313,11 @@ type CharacterFragment struct {
 	PrimaryFunction string %[1]cjson:"primaryFunction"%[1]c
 }
 
-const getCharacters = %[1]cquery getCharacters {
-    characters {
-        ... on Human {
-            ... on Human {
-                homePlanet
-            }
-        }
-        ... on Droid {
-            primaryFunction
-        }
-        ... on Character {
-            name
-        }
-    }
-}%[1]c
+const getCharacters = %[1]cquery getCharacters{characters{...on Human{...on Human{homePlanet}}...on Droid{primaryFunction}...on Character{name}}}%[1]c
 
 type CharacterClient interface {
 	GetCharacters(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error)
 }
 
 func (c *characterClient) GetCharacters(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1190,6 +1326,22 @@ func (c *characterClient) GetCharacters(ctx context.Context, header http.Header)
 	return c.ctrl.Execute(ctx, getCharacters, params, header)
 }
 
+func (c *characterClient) GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error) {
+	res, err := c.GetCharacters(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCharactersResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCharactersResponse struct {
 	Data   GetCharactersData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -1218,9 +1370,9 @@ type characterClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CharacterClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CharacterClient {
 	return &characterClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1247,6 +1399,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1257,22 +1410,11 @@ type CharacterFragment struct {
 	PrimaryFunction string %[1]cjson:"primaryFunction"%[1]c
 }
 
-const getCharacters = %[1]cquery getCharacters {
-    characters {
-        ... on Human {
-            homePlanet
-        }
-        ... on Droid {
-            primaryFunction
-        }
-        ... on Character {
-            name
-        }
-    }
-}%[1]c
+const getCharacters = %[1]cquery getCharacters{characters{...on Human{homePlanet}...on Droid{primaryFunction}...on Character{name}}}%[1]c
 
 type CharacterClient interface {
 	GetCharacters(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error)
 }
 
 func (c *characterClient) GetCharacters(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1281,6 +1423,22 @@ func (c *characterClient) GetCharacters(ctx context.Context, header http.Header)
 	return c.ctrl.Execute(ctx, getCharacters, params, header)
 }
 
+func (c *characterClient) GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error) {
+	res, err := c.GetCharacters(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCharactersResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCharactersResponse struct {
 	Data   GetCharactersData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -1309,9 +1467,9 @@ type characterClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CharacterClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CharacterClient {
 	return &characterClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1338,6 +1496,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1350,16 +1509,130 @@ type Response struct {
 	SuperType CharacterFragment %[1]cjson:"superType"%[1]c
 }
 
-const getCharactersId = %[1]cquery getCharactersId {
-    characters {
-        superType {
-            id
-        }
-    }
-}%[1]c
+const getCharactersId = %[1]cquery getCharactersId{characters{superType{id}}}%[1]c
+
+type CharacterClient interface {
+	GetCharactersId(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCharactersIdResponse(ctx context.Context, header http.Header) (*GetCharactersIdResponse, error)
+}
+
+func (c *characterClient) GetCharactersId(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getCharactersId, params, header)
+}
+
+func (c *characterClient) GetCharactersIdResponse(ctx context.Context, header http.Header) (*GetCharactersIdResponse, error) {
+	res, err := c.GetCharactersId(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCharactersIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetCharactersIdResponse struct {
+	Data   GetCharactersIdData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError      %[1]cjson:"errors"%[1]c
+}
+
+type GetCharactersIdData struct {
+	Characters Response %[1]cjson:"characters"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type characterClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CharacterClient {
+	return &characterClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_Interface_No_Implementation_DiscriminatedUnions(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/interface_no_impl/schema.graphql")
+	query := loadQuery(t, schema, "test/interface_no_impl/query.graphql")
+	info := AdditionalInfo{
+		PackageName:         "grafik_client",
+		ClientName:          "CharacterClient",
+		DiscriminatedUnions: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Character interface {
+	isCharacter()
+}
+
+type CharacterFragment struct {
+	Value Character
+}
+
+func (u *CharacterFragment) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		u.Value = nil
+		return nil
+	}
+	var typename struct {
+		TypeName string %[1]cjson:"__typename"%[1]c
+	}
+	if err := json.Unmarshal(data, &typename); err != nil {
+		return err
+	}
+	return fmt.Errorf("unexpected __typename %%q of CharacterFragment", typename.TypeName)
+}
+
+type Response struct {
+	SuperType CharacterFragment %[1]cjson:"superType"%[1]c
+}
+
+const getCharactersId = %[1]cquery getCharactersId{characters{superType{__typename id}}}%[1]c
 
 type CharacterClient interface {
 	GetCharactersId(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCharactersIdResponse(ctx context.Context, header http.Header) (*GetCharactersIdResponse, error)
 }
 
 func (c *characterClient) GetCharactersId(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1368,6 +1641,22 @@ func (c *characterClient) GetCharactersId(ctx context.Context, header http.Heade
 	return c.ctrl.Execute(ctx, getCharactersId, params, header)
 }
 
+func (c *characterClient) GetCharactersIdResponse(ctx context.Context, header http.Header) (*GetCharactersIdResponse, error) {
+	res, err := c.GetCharactersId(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCharactersIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCharactersIdResponse struct {
 	Data   GetCharactersIdData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError      %[1]cjson:"errors"%[1]c
@@ -1396,14 +1685,15 @@ type characterClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CharacterClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CharacterClient {
 	return &characterClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
 
 	assert.Equal(t, expOut, out)
+	typeCheck(t, out)
 }
 
 func TestEvaluator_InterfaceWithSelectionSet(t *testing.T) {
@@ -1425,6 +1715,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1441,33 +1732,11 @@ type PlanetFragment struct {
 	Age         int    %[1]cjson:"age"%[1]c
 }
 
-const getCharacters = %[1]cquery getCharacters {
-    characters {
-        ... on Human {
-            homePlanet
-        }
-        ... on Droid {
-            primaryFunction
-        }
-        ... on Character {
-            name
-        }
-    }
-    planets {
-        ... on IcePlanet {
-            temperature
-        }
-        ... on RockyPlanet {
-            age
-        }
-        ... on Planet {
-            name
-        }
-    }
-}%[1]c
+const getCharacters = %[1]cquery getCharacters{characters{...on Human{homePlanet}...on Droid{primaryFunction}...on Character{name}}planets{...on IcePlanet{temperature}...on RockyPlanet{age}...on Planet{name}}}%[1]c
 
 type PlanetClient interface {
 	GetCharacters(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error)
 }
 
 func (c *planetClient) GetCharacters(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1476,6 +1745,22 @@ func (c *planetClient) GetCharacters(ctx context.Context, header http.Header) (*
 	return c.ctrl.Execute(ctx, getCharacters, params, header)
 }
 
+func (c *planetClient) GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error) {
+	res, err := c.GetCharacters(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCharactersResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCharactersResponse struct {
 	Data   GetCharactersData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -1505,9 +1790,9 @@ type planetClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) PlanetClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) PlanetClient {
 	return &planetClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1534,6 +1819,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1543,19 +1829,11 @@ type CharacterResultUnion struct {
 	PrimaryFunction string %[1]cjson:"primaryFunction"%[1]c
 }
 
-const getCharacters = %[1]cquery getCharacters {
-    characters {
-        ... on Human {
-            homePlanet
-        }
-        ... on Droid {
-            primaryFunction
-        }
-    }
-}%[1]c
+const getCharacters = %[1]cquery getCharacters{characters{...on Human{homePlanet}...on Droid{primaryFunction}}}%[1]c
 
 type CharacterClient interface {
 	GetCharacters(ctx context.Context, header http.Header) (*http.Response, error)
+	GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error)
 }
 
 func (c *characterClient) GetCharacters(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1564,6 +1842,22 @@ func (c *characterClient) GetCharacters(ctx context.Context, header http.Header)
 	return c.ctrl.Execute(ctx, getCharacters, params, header)
 }
 
+func (c *characterClient) GetCharactersResponse(ctx context.Context, header http.Header) (*GetCharactersResponse, error) {
+	res, err := c.GetCharacters(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetCharactersResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetCharactersResponse struct {
 	Data   GetCharactersData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError    %[1]cjson:"errors"%[1]c
@@ -1592,9 +1886,9 @@ type characterClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CharacterClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CharacterClient {
 	return &characterClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1621,6 +1915,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1633,29 +1928,11 @@ type Rocket struct {
 	Name        *string %[1]cjson:"name"%[1]c
 }
 
-const getShortRocketInfo = %[1]cquery GetShortRocketInfo {
-    rockets {
-        ...RocketShortInfo
-    }
-}
-fragment RocketShortInfo on Rocket {
-    id
-    name
-    description
-    ...AdditionalRocketInfo
-}
-fragment AdditionalRocketInfo on Rocket {
-    country
-    ... on Rocket {
-        ...InformatoryRocketInfo
-    }
-}
-fragment InformatoryRocketInfo on Rocket {
-    active
-}%[1]c
+const getShortRocketInfo = %[1]cquery GetShortRocketInfo{rockets{...RocketShortInfo}}fragment RocketShortInfo on Rocket{id name description...AdditionalRocketInfo}fragment AdditionalRocketInfo on Rocket{country...on Rocket{...InformatoryRocketInfo}}fragment InformatoryRocketInfo on Rocket{active}%[1]c
 
 type RocketClient interface {
 	GetShortRocketInfo(ctx context.Context, header http.Header) (*http.Response, error)
+	GetShortRocketInfoResponse(ctx context.Context, header http.Header) (*GetShortRocketInfoResponse, error)
 }
 
 func (c *rocketClient) GetShortRocketInfo(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1664,6 +1941,22 @@ func (c *rocketClient) GetShortRocketInfo(ctx context.Context, header http.Heade
 	return c.ctrl.Execute(ctx, getShortRocketInfo, params, header)
 }
 
+func (c *rocketClient) GetShortRocketInfoResponse(ctx context.Context, header http.Header) (*GetShortRocketInfoResponse, error) {
+	res, err := c.GetShortRocketInfo(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetShortRocketInfoResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetShortRocketInfoResponse struct {
 	Data   *GetShortRocketInfoData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError         %[1]cjson:"errors"%[1]c
@@ -1692,9 +1985,9 @@ type rocketClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) RocketClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
 	return &rocketClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1721,6 +2014,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1746,28 +2040,11 @@ type UserFragment struct {
 	TotalCommits int    %[1]cjson:"totalCommits"%[1]c
 }
 
-const getRepositoryInformation = %[1]cquery getRepositoryInformation {
-    repositories(first: 10) {
-        name
-        author {
-            ... on Author {
-                name
-            }
-        }
-        pullRequests(after: "ABC") {
-            branchName
-            collaborators(first: 5) {
-                id
-            }
-        }
-        users(before: "123") {
-            totalCommits
-        }
-    }
-}%[1]c
+const getRepositoryInformation = %[1]cquery getRepositoryInformation{repositories(first:10){name author{...on Author{name}}pullRequests(after:"ABC"){branchName collaborators(first:5){id}}users(before:"123"){totalCommits}}}%[1]c
 
 type GitClient interface {
 	GetRepositoryInformation(ctx context.Context, header http.Header) (*http.Response, error)
+	GetRepositoryInformationResponse(ctx context.Context, header http.Header) (*GetRepositoryInformationResponse, error)
 }
 
 func (c *gitClient) GetRepositoryInformation(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -1776,6 +2053,22 @@ func (c *gitClient) GetRepositoryInformation(ctx context.Context, header http.He
 	return c.ctrl.Execute(ctx, getRepositoryInformation, params, header)
 }
 
+func (c *gitClient) GetRepositoryInformationResponse(ctx context.Context, header http.Header) (*GetRepositoryInformationResponse, error) {
+	res, err := c.GetRepositoryInformation(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRepositoryInformationResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetRepositoryInformationResponse struct {
 	Data   GetRepositoryInformationData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError               %[1]cjson:"errors"%[1]c
@@ -1804,9 +2097,9 @@ type gitClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) GitClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) GitClient {
 	return &gitClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1833,6 +2126,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1841,14 +2135,11 @@ type File struct {
 	Name string %[1]cjson:"name"%[1]c
 }
 
-const getFileNameWithId = %[1]cquery GetFileNameWithId($id: ID!) {
-    getFile(id: $id) {
-        name 
-    }
-}%[1]c
+const getFileNameWithId = %[1]cquery GetFileNameWithId($id:ID!){getFile(id:$id){name}}%[1]c
 
 type CommentsClient interface {
 	GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error)
 }
 
 func (c *commentsClient) GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error) {
@@ -1858,6 +2149,22 @@ func (c *commentsClient) GetFileNameWithId(ctx context.Context, id string, heade
 	return c.ctrl.Execute(ctx, getFileNameWithId, params, header)
 }
 
+func (c *commentsClient) GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error) {
+	res, err := c.GetFileNameWithId(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetFileNameWithIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetFileNameWithIdResponse struct {
 	Data   GetFileNameWithIdData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError        %[1]cjson:"errors"%[1]c
@@ -1886,9 +2193,9 @@ type commentsClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CommentsClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CommentsClient {
 	return &commentsClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1915,6 +2222,7 @@ package grafik_client
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -1929,18 +2237,11 @@ type Meta struct {
 	TypeName string %[1]cjson:"__typename"%[1]c
 }
 
-const getFileNameWithId = %[1]cquery GetFileNameWithId($id: ID!) {
-    getFile(id: $id) {
-        name
-        __typename
-        meta {
-            __typename
-        }
-    }
-}%[1]c
+const getFileNameWithId = %[1]cquery GetFileNameWithId($id:ID!){getFile(id:$id){name __typename meta{__typename}}}%[1]c
 
 type FieldClient interface {
 	GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error)
 }
 
 func (c *fieldClient) GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error) {
@@ -1950,6 +2251,22 @@ func (c *fieldClient) GetFileNameWithId(ctx context.Context, id string, header h
 	return c.ctrl.Execute(ctx, getFileNameWithId, params, header)
 }
 
+func (c *fieldClient) GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error) {
+	res, err := c.GetFileNameWithId(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetFileNameWithIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetFileNameWithIdResponse struct {
 	Data   GetFileNameWithIdData %[1]cjson:"data"%[1]c
 	Errors []GraphQLError        %[1]cjson:"errors"%[1]c
@@ -1978,9 +2295,9 @@ type fieldClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) FieldClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) FieldClient {
 	return &fieldClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
 `, '`'))
@@ -1988,32 +2305,3134 @@ func New(endpoint string, client *http.Client) FieldClient {
 	assert.Equal(t, expOut, out)
 }
 
-func loadSchema(t *testing.T, schemaName string) *ast.Schema {
-	schemaLoc := path.Join("../", schemaName)
-	file, err := ioutil.ReadFile(schemaLoc)
-	assert.NoError(t, err)
-	assert.NotNil(t, file)
+func TestEvaluator_PerOperationTypes(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/per_operation_types/schema.graphql")
+	query := loadQuery(t, schema, "test/per_operation_types/query.graphql")
+	info := AdditionalInfo{
+		PackageName:       "grafik_client",
+		ClientName:        "SpaceXClient",
+		UsePointers:       false,
+		PerOperationTypes: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
 
-	return gqlparser.MustLoadSchema(&ast.Source{
-		Input: string(file),
-		Name:  path.Base(schemaName),
-	})
+type LaunchFilter struct {
+	MissionName string %[1]cjson:"mission_name"%[1]c
 }
 
-func loadQuery(t *testing.T, schema *ast.Schema, queryName string) *ast.QueryDocument {
-	queryLoc := path.Join("../", queryName)
-	file, err := ioutil.ReadFile(queryLoc)
-	assert.NoError(t, err)
-	assert.NotNil(t, file)
+type LaunchStatus string
 
-	return gqlparser.MustLoadQuery(schema, string(file))
+const (
+	SUCCESS LaunchStatus = "SUCCESS"
+	FAILURE LaunchStatus = "FAILURE"
+)
+
+const getRocketNames = %[1]cquery getRocketNames{rockets{name}}%[1]c
+
+const getRocketsWithEngines = %[1]cquery getRocketsWithEngines($limit:Int){rockets(limit:$limit){id...RocketCountry engines{number}}}fragment RocketCountry on Rocket{country engines{type}}%[1]c
+
+const getLaunches = %[1]cquery getLaunches($filter:LaunchFilter){launches(filter:$filter){__typename mission_name status vehicle:rocket{name engines{type}}}}%[1]c
+
+type SpaceXClient interface {
+	GetRocketNames(ctx context.Context, header http.Header) (*http.Response, error)
+	GetRocketNamesResponse(ctx context.Context, header http.Header) (*GetRocketNamesResponse, error)
+	GetRocketsWithEngines(ctx context.Context, limit int, header http.Header) (*http.Response, error)
+	GetRocketsWithEnginesResponse(ctx context.Context, limit int, header http.Header) (*GetRocketsWithEnginesResponse, error)
+	GetLaunches(ctx context.Context, filter LaunchFilter, header http.Header) (*http.Response, error)
+	GetLaunchesResponse(ctx context.Context, filter LaunchFilter, header http.Header) (*GetLaunchesResponse, error)
 }
 
-func getSourceString(t *testing.T, e Evaluator) string {
-	fileContent := e.Generate()
-	src := &bytes.Buffer{}
-	_, err := fileContent.WriteTo(src)
-	assert.NoError(t, err)
+func (c *spaceXClient) GetRocketNames(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
 
-	return src.String()
+	return c.ctrl.Execute(ctx, getRocketNames, params, header)
+}
+
+func (c *spaceXClient) GetRocketNamesResponse(ctx context.Context, header http.Header) (*GetRocketNamesResponse, error) {
+	res, err := c.GetRocketNames(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketNamesResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *spaceXClient) GetRocketsWithEngines(ctx context.Context, limit int, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["limit"] = limit
+
+	return c.ctrl.Execute(ctx, getRocketsWithEngines, params, header)
+}
+
+func (c *spaceXClient) GetRocketsWithEnginesResponse(ctx context.Context, limit int, header http.Header) (*GetRocketsWithEnginesResponse, error) {
+	res, err := c.GetRocketsWithEngines(ctx, limit, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketsWithEnginesResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *spaceXClient) GetLaunches(ctx context.Context, filter LaunchFilter, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["filter"] = filter
+
+	return c.ctrl.Execute(ctx, getLaunches, params, header)
+}
+
+func (c *spaceXClient) GetLaunchesResponse(ctx context.Context, filter LaunchFilter, header http.Header) (*GetLaunchesResponse, error) {
+	res, err := c.GetLaunches(ctx, filter, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetLaunchesResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketNamesResponse struct {
+	Data   GetRocketNamesData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError     %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketNamesData struct {
+	Rockets []GetRocketNamesRockets %[1]cjson:"rockets"%[1]c
+}
+
+type GetRocketNamesRockets struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type GetRocketsWithEnginesResponse struct {
+	Data   GetRocketsWithEnginesData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError            %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketsWithEnginesData struct {
+	Rockets []GetRocketsWithEnginesRockets %[1]cjson:"rockets"%[1]c
+}
+
+type GetRocketsWithEnginesRockets struct {
+	Id      string                              %[1]cjson:"id"%[1]c
+	Country string                              %[1]cjson:"country"%[1]c
+	Engines GetRocketsWithEnginesRocketsEngines %[1]cjson:"engines"%[1]c
+}
+
+type GetRocketsWithEnginesRocketsEngines struct {
+	Type   string %[1]cjson:"type"%[1]c
+	Number int    %[1]cjson:"number"%[1]c
+}
+
+type GetLaunchesResponse struct {
+	Data   GetLaunchesData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError  %[1]cjson:"errors"%[1]c
+}
+
+type GetLaunchesData struct {
+	Launches []GetLaunchesLaunches %[1]cjson:"launches"%[1]c
+}
+
+type GetLaunchesLaunches struct {
+	TypeName    string                     %[1]cjson:"__typename"%[1]c
+	MissionName string                     %[1]cjson:"mission_name"%[1]c
+	Status      LaunchStatus               %[1]cjson:"status"%[1]c
+	Vehicle     GetLaunchesLaunchesVehicle %[1]cjson:"vehicle"%[1]c
+}
+
+type GetLaunchesLaunchesVehicle struct {
+	Name    string                            %[1]cjson:"name"%[1]c
+	Engines GetLaunchesLaunchesVehicleEngines %[1]cjson:"engines"%[1]c
+}
+
+type GetLaunchesLaunchesVehicleEngines struct {
+	Type string %[1]cjson:"type"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type spaceXClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) SpaceXClient {
+	return &spaceXClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_ScalarBindings(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/scalar_binding/schema.graphql")
+	query := loadQuery(t, schema, "test/scalar_binding/query.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "EventsClient",
+		UsePointers: false,
+		ScalarBindings: map[string]string{
+			"DateTime": "time.Time",
+			"UUID":     "github.com/google/uuid.UUID",
+			"JSON":     "encoding/json.RawMessage",
+		},
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type DateTime = time.Time
+
+type Event struct {
+	Id         UUID     %[1]cjson:"id"%[1]c
+	OccurredAt DateTime %[1]cjson:"occurredAt"%[1]c
+	Payload    JSON     %[1]cjson:"payload"%[1]c
+	Score      float64  %[1]cjson:"score"%[1]c
+	Tags       []Tag    %[1]cjson:"tags"%[1]c
+}
+
+type JSON = json.RawMessage
+
+type Tag interface {
+}
+
+type UUID = uuid.UUID
+
+const getEvents = %[1]cquery getEvents($after:DateTime){events(after:$after){id occurredAt payload score tags}}%[1]c
+
+type EventsClient interface {
+	GetEvents(ctx context.Context, after DateTime, header http.Header) (*http.Response, error)
+	GetEventsResponse(ctx context.Context, after DateTime, header http.Header) (*GetEventsResponse, error)
+}
+
+func (c *eventsClient) GetEvents(ctx context.Context, after DateTime, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["after"] = after
+
+	return c.ctrl.Execute(ctx, getEvents, params, header)
+}
+
+func (c *eventsClient) GetEventsResponse(ctx context.Context, after DateTime, header http.Header) (*GetEventsResponse, error) {
+	res, err := c.GetEvents(ctx, after, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetEventsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetEventsResponse struct {
+	Data   GetEventsData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetEventsData struct {
+	Events []Event %[1]cjson:"events"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type eventsClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) EventsClient {
+	return &eventsClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_ScalarBindings_InvalidType(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/scalar_binding/schema.graphql")
+	query := loadQuery(t, schema, "test/scalar_binding/query.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "EventsClient",
+		ScalarBindings: map[string]string{
+			"DateTime": "time.",
+		},
+	}
+	e := New(schema, query, info)
+
+	_, err := e.Generate()
+	var diagnostics Diagnostics
+	assert.True(t, errors.As(err, &diagnostics))
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, 17, diagnostics[0].Position.Line)
+	assert.EqualError(t, err, "schema.graphql:17:8: failed to bind GraphQL scalar DateTime. Cause: invalid Go type \"time.\" - expected [import/path.]TypeName")
+}
+
+func TestEvaluator_Diagnostics(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/fragment/schema.graphql")
+	query := gqlparser.MustLoadQuery(schema, `query getRockets {
+    ...RocketsInfo
+}
+
+query getRocketNames {
+    ... on Query {
+        rockets {
+            name
+        }
+    }
+}
+
+fragment RocketsInfo on Query {
+    rockets {
+        id
+    }
+}`)
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	out, err := e.Generate()
+	assert.Nil(t, out)
+	assert.EqualError(t, err, "input:2:8: fragments selected directly in the operation are supported only with per operation types\n"+
+		"input:6:9: fragments selected directly in the operation are supported only with per operation types")
+}
+
+func TestEvaluator_PerOperationTypes_NameCollision(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/fragment/schema.graphql")
+	query := gqlparser.MustLoadQuery(schema, `query getRockets {
+    data: rockets {
+        id
+    }
+}
+
+query getRocketNames {
+    rocket_names: rockets {
+        name
+    }
+    rocketNames: rockets(limit: 10) {
+        name
+    }
+}`)
+	info := AdditionalInfo{
+		PackageName:       "grafik_client",
+		ClientName:        "RocketClient",
+		PerOperationTypes: true,
+	}
+	e := New(schema, query, info)
+
+	out, err := e.Generate()
+	assert.Nil(t, out)
+	assert.EqualError(t, err, "input:1:1: type GetRocketsData is generated more than once for operation getRockets - use different aliases of its fields\n"+
+		"input:7:1: type GetRocketNamesRocketNames is generated more than once for operation getRocketNames - use different aliases of its fields")
+}
+
+func TestEvaluator_NullablePointers(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/nullable/schema.graphql")
+	query := loadQuery(t, schema, "test/nullable/query.graphql")
+	info := AdditionalInfo{
+		PackageName:      "grafik_client",
+		ClientName:       "RocketClient",
+		NullablePointers: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Engines struct {
+	Number int     %[1]cjson:"number"%[1]c
+	Layout [][]int %[1]cjson:"layout"%[1]c
+}
+
+type Launchpad struct {
+	Name   string  %[1]cjson:"name"%[1]c
+	Status *string %[1]cjson:"status"%[1]c
+}
+
+type Rocket struct {
+	Id          string    %[1]cjson:"id"%[1]c
+	Name        *string   %[1]cjson:"name"%[1]c
+	Active      bool      %[1]cjson:"active"%[1]c
+	SuccessRate *float64  %[1]cjson:"successRate"%[1]c
+	Tags        []*string %[1]cjson:"tags"%[1]c
+	Engines     *Engines  %[1]cjson:"engines"%[1]c
+	TypeName    string    %[1]cjson:"__typename"%[1]c
+}
+
+type RocketFilter struct {
+	Name   *string %[1]cjson:"name"%[1]c
+	Active bool    %[1]cjson:"active"%[1]c
+}
+
+type RocketInput struct {
+	Name *string  %[1]cjson:"name"%[1]c
+	Tags []string %[1]cjson:"tags"%[1]c
+}
+
+const getRockets = %[1]cquery getRockets($filter:RocketFilter){rockets(filter:$filter){__typename id name active successRate tags engines{number layout}}launchpads{name status}}%[1]c
+
+const updateRocket = %[1]cmutation updateRocket($id:ID!$input:RocketInput!){updateRocket(id:$id input:$input){id}}%[1]c
+
+type RocketClient interface {
+	GetRockets(ctx context.Context, filter RocketFilter, header http.Header) (*http.Response, error)
+	GetRocketsResponse(ctx context.Context, filter RocketFilter, header http.Header) (*GetRocketsResponse, error)
+	UpdateRocket(ctx context.Context, id string, input RocketInput, header http.Header) (*http.Response, error)
+	UpdateRocketResponse(ctx context.Context, id string, input RocketInput, header http.Header) (*UpdateRocketResponse, error)
+}
+
+func (c *rocketClient) GetRockets(ctx context.Context, filter RocketFilter, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["filter"] = filter
+
+	return c.ctrl.Execute(ctx, getRockets, params, header)
+}
+
+func (c *rocketClient) GetRocketsResponse(ctx context.Context, filter RocketFilter, header http.Header) (*GetRocketsResponse, error) {
+	res, err := c.GetRockets(ctx, filter, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *rocketClient) UpdateRocket(ctx context.Context, id string, input RocketInput, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 2)
+	params["id"] = id
+	params["input"] = input
+
+	return c.ctrl.Execute(ctx, updateRocket, params, header)
+}
+
+func (c *rocketClient) UpdateRocketResponse(ctx context.Context, id string, input RocketInput, header http.Header) (*UpdateRocketResponse, error) {
+	res, err := c.UpdateRocket(ctx, id, input, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out UpdateRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketsResponse struct {
+	Data   GetRocketsData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketsData struct {
+	Rockets    []Rocket     %[1]cjson:"rockets"%[1]c
+	Launchpads []*Launchpad %[1]cjson:"launchpads"%[1]c
+}
+
+type UpdateRocketResponse struct {
+	Data   UpdateRocketData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError   %[1]cjson:"errors"%[1]c
+}
+
+type UpdateRocketData struct {
+	UpdateRocket Rocket %[1]cjson:"updateRocket"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                  %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation  %[1]cjson:"locations"%[1]c
+	Extensions *GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code *string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_NullablePointers_PerOperationTypes(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/nullable/schema.graphql")
+	query := loadQuery(t, schema, "test/nullable/query.graphql")
+	info := AdditionalInfo{
+		PackageName:       "grafik_client",
+		ClientName:        "RocketClient",
+		NullablePointers:  true,
+		PerOperationTypes: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type RocketFilter struct {
+	Name   *string %[1]cjson:"name"%[1]c
+	Active bool    %[1]cjson:"active"%[1]c
+}
+
+type RocketInput struct {
+	Name *string  %[1]cjson:"name"%[1]c
+	Tags []string %[1]cjson:"tags"%[1]c
+}
+
+const getRockets = %[1]cquery getRockets($filter:RocketFilter){rockets(filter:$filter){__typename id name active successRate tags engines{number layout}}launchpads{name status}}%[1]c
+
+const updateRocket = %[1]cmutation updateRocket($id:ID!$input:RocketInput!){updateRocket(id:$id input:$input){id}}%[1]c
+
+type RocketClient interface {
+	GetRockets(ctx context.Context, filter RocketFilter, header http.Header) (*http.Response, error)
+	GetRocketsResponse(ctx context.Context, filter RocketFilter, header http.Header) (*GetRocketsResponse, error)
+	UpdateRocket(ctx context.Context, id string, input RocketInput, header http.Header) (*http.Response, error)
+	UpdateRocketResponse(ctx context.Context, id string, input RocketInput, header http.Header) (*UpdateRocketResponse, error)
+}
+
+func (c *rocketClient) GetRockets(ctx context.Context, filter RocketFilter, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["filter"] = filter
+
+	return c.ctrl.Execute(ctx, getRockets, params, header)
+}
+
+func (c *rocketClient) GetRocketsResponse(ctx context.Context, filter RocketFilter, header http.Header) (*GetRocketsResponse, error) {
+	res, err := c.GetRockets(ctx, filter, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *rocketClient) UpdateRocket(ctx context.Context, id string, input RocketInput, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 2)
+	params["id"] = id
+	params["input"] = input
+
+	return c.ctrl.Execute(ctx, updateRocket, params, header)
+}
+
+func (c *rocketClient) UpdateRocketResponse(ctx context.Context, id string, input RocketInput, header http.Header) (*UpdateRocketResponse, error) {
+	res, err := c.UpdateRocket(ctx, id, input, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out UpdateRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketsResponse struct {
+	Data   GetRocketsData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketsData struct {
+	Rockets    []GetRocketsRockets     %[1]cjson:"rockets"%[1]c
+	Launchpads []*GetRocketsLaunchpads %[1]cjson:"launchpads"%[1]c
+}
+
+type GetRocketsRockets struct {
+	TypeName    string                    %[1]cjson:"__typename"%[1]c
+	Id          string                    %[1]cjson:"id"%[1]c
+	Name        *string                   %[1]cjson:"name"%[1]c
+	Active      bool                      %[1]cjson:"active"%[1]c
+	SuccessRate *float64                  %[1]cjson:"successRate"%[1]c
+	Tags        []*string                 %[1]cjson:"tags"%[1]c
+	Engines     *GetRocketsRocketsEngines %[1]cjson:"engines"%[1]c
+}
+
+type GetRocketsRocketsEngines struct {
+	Number int     %[1]cjson:"number"%[1]c
+	Layout [][]int %[1]cjson:"layout"%[1]c
+}
+
+type GetRocketsLaunchpads struct {
+	Name   string  %[1]cjson:"name"%[1]c
+	Status *string %[1]cjson:"status"%[1]c
+}
+
+type UpdateRocketResponse struct {
+	Data   UpdateRocketData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError   %[1]cjson:"errors"%[1]c
+}
+
+type UpdateRocketData struct {
+	UpdateRocket UpdateRocketUpdateRocket %[1]cjson:"updateRocket"%[1]c
+}
+
+type UpdateRocketUpdateRocket struct {
+	Id string %[1]cjson:"id"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                  %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation  %[1]cjson:"locations"%[1]c
+	Extensions *GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code *string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_OptionalInputs(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/optional_inputs/schema.graphql")
+	query := loadQuery(t, schema, "test/optional_inputs/query.graphql")
+	info := AdditionalInfo{
+		PackageName:    "grafik_client",
+		ClientName:     "RocketClient",
+		OptionalInputs: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Rocket struct {
+	Id   string %[1]cjson:"id"%[1]c
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type RocketInput struct {
+	Id         string   %[1]cjson:"id"%[1]c
+	Name       *string  %[1]cjson:"name"%[1]c
+	Active     *bool    %[1]cjson:"active"%[1]c
+	Tags       []string %[1]cjson:"tags"%[1]c
+	NullFields []string %[1]cjson:"-"%[1]c
+}
+
+func (i RocketInput) MarshalJSON() ([]byte, error) {
+	type alias RocketInput
+	unset := map[string]bool{
+		"name":   i.Name == nil,
+		"active": i.Active == nil,
+		"tags":   i.Tags == nil,
+	}
+	return GraphqlClient.MarshalInput(alias(i), unset, i.NullFields)
+}
+
+const updateRocket = %[1]cmutation UpdateRocket($input:RocketInput!){updateRocket(input:$input){id name}}%[1]c
+
+type RocketClient interface {
+	UpdateRocket(ctx context.Context, input RocketInput, header http.Header) (*http.Response, error)
+	UpdateRocketResponse(ctx context.Context, input RocketInput, header http.Header) (*UpdateRocketResponse, error)
+}
+
+func (c *rocketClient) UpdateRocket(ctx context.Context, input RocketInput, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["input"] = input
+
+	return c.ctrl.Execute(ctx, updateRocket, params, header)
+}
+
+func (c *rocketClient) UpdateRocketResponse(ctx context.Context, input RocketInput, header http.Header) (*UpdateRocketResponse, error) {
+	res, err := c.UpdateRocket(ctx, input, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out UpdateRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type UpdateRocketResponse struct {
+	Data   UpdateRocketData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError   %[1]cjson:"errors"%[1]c
+}
+
+type UpdateRocketData struct {
+	UpdateRocket Rocket %[1]cjson:"updateRocket"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_DiscriminatedUnions(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/discriminated_unions/schema.graphql")
+	query := loadQuery(t, schema, "test/discriminated_unions/query.graphql")
+	info := AdditionalInfo{
+		PackageName:         "grafik_client",
+		ClientName:          "StarWarsClient",
+		DiscriminatedUnions: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type CharacterHuman struct {
+	Id       string %[1]cjson:"id"%[1]c
+	TypeName string %[1]cjson:"__typename"%[1]c
+}
+
+type CharacterDroid struct {
+	Id              string %[1]cjson:"id"%[1]c
+	PrimaryFunction string %[1]cjson:"primaryFunction"%[1]c
+	TypeName        string %[1]cjson:"__typename"%[1]c
+}
+
+type Character interface {
+	isCharacter()
+}
+
+func (CharacterHuman) isCharacter() {}
+
+func (CharacterDroid) isCharacter() {}
+
+type CharacterFragment struct {
+	Value Character
+}
+
+func (u *CharacterFragment) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		u.Value = nil
+		return nil
+	}
+	var typename struct {
+		TypeName string %[1]cjson:"__typename"%[1]c
+	}
+	if err := json.Unmarshal(data, &typename); err != nil {
+		return err
+	}
+	switch typename.TypeName {
+	case "Human":
+		var v CharacterHuman
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Droid":
+		var v CharacterDroid
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	}
+	return fmt.Errorf("unexpected __typename %%q of CharacterFragment", typename.TypeName)
+}
+
+type Droid struct {
+	Id              string %[1]cjson:"id"%[1]c
+	PrimaryFunction string %[1]cjson:"primaryFunction"%[1]c
+	TypeName        string %[1]cjson:"__typename"%[1]c
+}
+
+type SearchResultHuman struct {
+	Name   string  %[1]cjson:"name"%[1]c
+	Height float64 %[1]cjson:"height"%[1]c
+}
+
+type SearchResultDroid struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type SearchResultStarship struct {
+	Name   string %[1]cjson:"name"%[1]c
+	Length int    %[1]cjson:"length"%[1]c
+}
+
+type SearchResult interface {
+	isSearchResult()
+}
+
+func (SearchResultHuman) isSearchResult() {}
+
+func (SearchResultDroid) isSearchResult() {}
+
+func (SearchResultStarship) isSearchResult() {}
+
+type SearchResultUnion struct {
+	Value SearchResult
+}
+
+func (u *SearchResultUnion) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		u.Value = nil
+		return nil
+	}
+	var typename struct {
+		TypeName string %[1]cjson:"__typename"%[1]c
+	}
+	if err := json.Unmarshal(data, &typename); err != nil {
+		return err
+	}
+	switch typename.TypeName {
+	case "Human":
+		var v SearchResultHuman
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Droid":
+		var v SearchResultDroid
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Starship":
+		var v SearchResultStarship
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	}
+	return fmt.Errorf("unexpected __typename %%q of SearchResultUnion", typename.TypeName)
+}
+
+const search = %[1]cquery search($text:String!){search(text:$text){__typename...on Character{name}...on Human{height}...on Starship{name length}}}%[1]c
+
+const getHero = %[1]cquery getHero{hero{__typename id...droidFields}}fragment droidFields on Droid{primaryFunction}%[1]c
+
+type StarWarsClient interface {
+	Search(ctx context.Context, text string, header http.Header) (*http.Response, error)
+	SearchResponse(ctx context.Context, text string, header http.Header) (*SearchResponse, error)
+	GetHero(ctx context.Context, header http.Header) (*http.Response, error)
+	GetHeroResponse(ctx context.Context, header http.Header) (*GetHeroResponse, error)
+}
+
+func (c *starWarsClient) Search(ctx context.Context, text string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["text"] = text
+
+	return c.ctrl.Execute(ctx, search, params, header)
+}
+
+func (c *starWarsClient) SearchResponse(ctx context.Context, text string, header http.Header) (*SearchResponse, error) {
+	res, err := c.Search(ctx, text, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out SearchResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *starWarsClient) GetHero(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getHero, params, header)
+}
+
+func (c *starWarsClient) GetHeroResponse(ctx context.Context, header http.Header) (*GetHeroResponse, error) {
+	res, err := c.GetHero(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetHeroResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type SearchResponse struct {
+	Data   SearchData     %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type SearchData struct {
+	Search []SearchResultUnion %[1]cjson:"search"%[1]c
+}
+
+type GetHeroResponse struct {
+	Data   GetHeroData    %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetHeroData struct {
+	Hero CharacterFragment %[1]cjson:"hero"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type starWarsClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) StarWarsClient {
+	return &starWarsClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_DiscriminatedUnions_PerOperationTypes(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/discriminated_unions/schema.graphql")
+	query := loadQuery(t, schema, "test/discriminated_unions/query.graphql")
+	info := AdditionalInfo{
+		PackageName:         "grafik_client",
+		ClientName:          "StarWarsClient",
+		DiscriminatedUnions: true,
+		PerOperationTypes:   true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+const search = %[1]cquery search($text:String!){search(text:$text){__typename...on Character{name}...on Human{height}...on Starship{name length}}}%[1]c
+
+const getHero = %[1]cquery getHero{hero{__typename id...droidFields}}fragment droidFields on Droid{primaryFunction}%[1]c
+
+type StarWarsClient interface {
+	Search(ctx context.Context, text string, header http.Header) (*http.Response, error)
+	SearchResponse(ctx context.Context, text string, header http.Header) (*SearchResponse, error)
+	GetHero(ctx context.Context, header http.Header) (*http.Response, error)
+	GetHeroResponse(ctx context.Context, header http.Header) (*GetHeroResponse, error)
+}
+
+func (c *starWarsClient) Search(ctx context.Context, text string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["text"] = text
+
+	return c.ctrl.Execute(ctx, search, params, header)
+}
+
+func (c *starWarsClient) SearchResponse(ctx context.Context, text string, header http.Header) (*SearchResponse, error) {
+	res, err := c.Search(ctx, text, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out SearchResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *starWarsClient) GetHero(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getHero, params, header)
+}
+
+func (c *starWarsClient) GetHeroResponse(ctx context.Context, header http.Header) (*GetHeroResponse, error) {
+	res, err := c.GetHero(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetHeroResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type SearchResponse struct {
+	Data   SearchData     %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type SearchData struct {
+	Search []SearchSearch %[1]cjson:"search"%[1]c
+}
+
+type SearchSearchHuman struct {
+	Name   string  %[1]cjson:"name"%[1]c
+	Height float64 %[1]cjson:"height"%[1]c
+}
+
+type SearchSearchDroid struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type SearchSearchStarship struct {
+	Name   string %[1]cjson:"name"%[1]c
+	Length int    %[1]cjson:"length"%[1]c
+}
+
+type SearchSearchValue interface {
+	isSearchSearchValue()
+}
+
+func (SearchSearchHuman) isSearchSearchValue() {}
+
+func (SearchSearchDroid) isSearchSearchValue() {}
+
+func (SearchSearchStarship) isSearchSearchValue() {}
+
+type SearchSearch struct {
+	Value SearchSearchValue
+}
+
+func (u *SearchSearch) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		u.Value = nil
+		return nil
+	}
+	var typename struct {
+		TypeName string %[1]cjson:"__typename"%[1]c
+	}
+	if err := json.Unmarshal(data, &typename); err != nil {
+		return err
+	}
+	switch typename.TypeName {
+	case "Human":
+		var v SearchSearchHuman
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Droid":
+		var v SearchSearchDroid
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Starship":
+		var v SearchSearchStarship
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	}
+	return fmt.Errorf("unexpected __typename %%q of SearchSearch", typename.TypeName)
+}
+
+type GetHeroResponse struct {
+	Data   GetHeroData    %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetHeroData struct {
+	Hero GetHeroHero %[1]cjson:"hero"%[1]c
+}
+
+type GetHeroHeroHuman struct {
+	TypeName string %[1]cjson:"__typename"%[1]c
+	Id       string %[1]cjson:"id"%[1]c
+}
+
+type GetHeroHeroDroid struct {
+	TypeName        string %[1]cjson:"__typename"%[1]c
+	Id              string %[1]cjson:"id"%[1]c
+	PrimaryFunction string %[1]cjson:"primaryFunction"%[1]c
+}
+
+type GetHeroHeroValue interface {
+	isGetHeroHeroValue()
+}
+
+func (GetHeroHeroHuman) isGetHeroHeroValue() {}
+
+func (GetHeroHeroDroid) isGetHeroHeroValue() {}
+
+type GetHeroHero struct {
+	Value GetHeroHeroValue
+}
+
+func (u *GetHeroHero) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		u.Value = nil
+		return nil
+	}
+	var typename struct {
+		TypeName string %[1]cjson:"__typename"%[1]c
+	}
+	if err := json.Unmarshal(data, &typename); err != nil {
+		return err
+	}
+	switch typename.TypeName {
+	case "Human":
+		var v GetHeroHeroHuman
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Droid":
+		var v GetHeroHeroDroid
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	}
+	return fmt.Errorf("unexpected __typename %%q of GetHeroHero", typename.TypeName)
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type starWarsClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) StarWarsClient {
+	return &starWarsClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_FragmentStructs(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/fragment_structs/schema.graphql")
+	query := loadQuery(t, schema, "test/fragment_structs/query.graphql")
+	info := AdditionalInfo{
+		PackageName:     "grafik_client",
+		ClientName:      "RocketClient",
+		FragmentStructs: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Engines struct {
+	Number int    %[1]cjson:"number"%[1]c
+	Type   string %[1]cjson:"type"%[1]c
+}
+
+type Rocket struct {
+	RocketSummary %[1]cjson:"-"%[1]c
+	RocketEngines %[1]cjson:"-"%[1]c
+	Country       string %[1]cjson:"country"%[1]c
+}
+
+func (s *Rocket) UnmarshalJSON(data []byte) error {
+	type alias Rocket
+	if err := json.Unmarshal(data, (*alias)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketSummary); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketEngines); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s Rocket) MarshalJSON() ([]byte, error) {
+	type alias Rocket
+	return GraphqlClient.MarshalFragments(alias(s), s.RocketSummary, s.RocketEngines)
+}
+
+type RocketEngines struct {
+	Id      string  %[1]cjson:"id"%[1]c
+	Engines Engines %[1]cjson:"engines"%[1]c
+}
+
+type RocketSummary struct {
+	Id   string %[1]cjson:"id"%[1]c
+	Name string %[1]cjson:"name"%[1]c
+}
+
+const getRockets = %[1]cquery getRockets{rockets{country...RocketSummary}}fragment RocketSummary on Rocket{id name}%[1]c
+
+const getRocket = %[1]cquery getRocket($id:ID!){rocket(id:$id){...RocketSummary...RocketEngines}}fragment RocketSummary on Rocket{id name}fragment RocketEngines on Rocket{id engines{number type}}%[1]c
+
+type RocketClient interface {
+	GetRockets(ctx context.Context, header http.Header) (*http.Response, error)
+	GetRocketsResponse(ctx context.Context, header http.Header) (*GetRocketsResponse, error)
+	GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error)
+}
+
+func (c *rocketClient) GetRockets(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getRockets, params, header)
+}
+
+func (c *rocketClient) GetRocketsResponse(ctx context.Context, header http.Header) (*GetRocketsResponse, error) {
+	res, err := c.GetRockets(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	return c.ctrl.Execute(ctx, getRocket, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketsResponse struct {
+	Data   GetRocketsData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketsData struct {
+	Rockets []Rocket %[1]cjson:"rockets"%[1]c
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket Rocket %[1]cjson:"rocket"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_FragmentStructs_PerOperationTypes(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/fragment_structs/schema.graphql")
+	query := loadQuery(t, schema, "test/fragment_structs/query.graphql")
+	info := AdditionalInfo{
+		PackageName:       "grafik_client",
+		ClientName:        "RocketClient",
+		FragmentStructs:   true,
+		PerOperationTypes: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type RocketEngines struct {
+	Id      string               %[1]cjson:"id"%[1]c
+	Engines RocketEnginesEngines %[1]cjson:"engines"%[1]c
+}
+
+type RocketEnginesEngines struct {
+	Number int    %[1]cjson:"number"%[1]c
+	Type   string %[1]cjson:"type"%[1]c
+}
+
+type RocketSummary struct {
+	Id   string %[1]cjson:"id"%[1]c
+	Name string %[1]cjson:"name"%[1]c
+}
+
+const getRockets = %[1]cquery getRockets{rockets{country...RocketSummary}}fragment RocketSummary on Rocket{id name}%[1]c
+
+const getRocket = %[1]cquery getRocket($id:ID!){rocket(id:$id){...RocketSummary...RocketEngines}}fragment RocketSummary on Rocket{id name}fragment RocketEngines on Rocket{id engines{number type}}%[1]c
+
+type RocketClient interface {
+	GetRockets(ctx context.Context, header http.Header) (*http.Response, error)
+	GetRocketsResponse(ctx context.Context, header http.Header) (*GetRocketsResponse, error)
+	GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error)
+}
+
+func (c *rocketClient) GetRockets(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getRockets, params, header)
+}
+
+func (c *rocketClient) GetRocketsResponse(ctx context.Context, header http.Header) (*GetRocketsResponse, error) {
+	res, err := c.GetRockets(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	return c.ctrl.Execute(ctx, getRocket, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketsResponse struct {
+	Data   GetRocketsData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketsData struct {
+	Rockets []GetRocketsRockets %[1]cjson:"rockets"%[1]c
+}
+
+type GetRocketsRockets struct {
+	RocketSummary %[1]cjson:"-"%[1]c
+	Country       string %[1]cjson:"country"%[1]c
+}
+
+func (s *GetRocketsRockets) UnmarshalJSON(data []byte) error {
+	type alias GetRocketsRockets
+	if err := json.Unmarshal(data, (*alias)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketSummary); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s GetRocketsRockets) MarshalJSON() ([]byte, error) {
+	type alias GetRocketsRockets
+	return GraphqlClient.MarshalFragments(alias(s), s.RocketSummary)
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket GetRocketRocket %[1]cjson:"rocket"%[1]c
+}
+
+type GetRocketRocket struct {
+	RocketSummary %[1]cjson:"-"%[1]c
+	RocketEngines %[1]cjson:"-"%[1]c
+}
+
+func (s *GetRocketRocket) UnmarshalJSON(data []byte) error {
+	type alias GetRocketRocket
+	if err := json.Unmarshal(data, (*alias)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketSummary); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketEngines); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s GetRocketRocket) MarshalJSON() ([]byte, error) {
+	type alias GetRocketRocket
+	return GraphqlClient.MarshalFragments(alias(s), s.RocketSummary, s.RocketEngines)
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_FragmentStructs_NestedFragments(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/fragment/schema.graphql")
+	query := loadQuery(t, schema, "test/fragment/query.graphql")
+	info := AdditionalInfo{
+		PackageName:     "grafik_client",
+		ClientName:      "RocketClient",
+		FragmentStructs: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Rocket struct {
+	RocketShortInfo %[1]cjson:"-"%[1]c
+}
+
+func (s *Rocket) UnmarshalJSON(data []byte) error {
+	type alias Rocket
+	if err := json.Unmarshal(data, (*alias)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketShortInfo); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketShortInfo.AdditionalRocketInfo); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketShortInfo.AdditionalRocketInfo.InformatoryRocketInfo); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s Rocket) MarshalJSON() ([]byte, error) {
+	type alias Rocket
+	return GraphqlClient.MarshalFragments(alias(s), s.RocketShortInfo, s.RocketShortInfo.AdditionalRocketInfo, s.RocketShortInfo.AdditionalRocketInfo.InformatoryRocketInfo)
+}
+
+type AdditionalRocketInfo struct {
+	InformatoryRocketInfo %[1]cjson:"-"%[1]c
+	Country               string %[1]cjson:"country"%[1]c
+}
+
+type InformatoryRocketInfo struct {
+	Active bool %[1]cjson:"active"%[1]c
+}
+
+type RocketShortInfo struct {
+	AdditionalRocketInfo %[1]cjson:"-"%[1]c
+	Description          string %[1]cjson:"description"%[1]c
+	Id                   string %[1]cjson:"id"%[1]c
+	Name                 string %[1]cjson:"name"%[1]c
+}
+
+const getShortRocketInfo = %[1]cquery GetShortRocketInfo{rockets{...RocketShortInfo}}fragment RocketShortInfo on Rocket{id name description...AdditionalRocketInfo}fragment AdditionalRocketInfo on Rocket{country...on Rocket{...InformatoryRocketInfo}}fragment InformatoryRocketInfo on Rocket{active}%[1]c
+
+type RocketClient interface {
+	GetShortRocketInfo(ctx context.Context, header http.Header) (*http.Response, error)
+	GetShortRocketInfoResponse(ctx context.Context, header http.Header) (*GetShortRocketInfoResponse, error)
+}
+
+func (c *rocketClient) GetShortRocketInfo(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getShortRocketInfo, params, header)
+}
+
+func (c *rocketClient) GetShortRocketInfoResponse(ctx context.Context, header http.Header) (*GetShortRocketInfoResponse, error) {
+	res, err := c.GetShortRocketInfo(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetShortRocketInfoResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetShortRocketInfoResponse struct {
+	Data   GetShortRocketInfoData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError         %[1]cjson:"errors"%[1]c
+}
+
+type GetShortRocketInfoData struct {
+	Rockets []Rocket %[1]cjson:"rockets"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+	typeCheck(t, out)
+}
+
+func TestEvaluator_Subscription(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/subscription/schema.graphql")
+	query := loadQuery(t, schema, "test/subscription/query.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Rocket struct {
+	Id   string %[1]cjson:"id"%[1]c
+	Name string %[1]cjson:"name"%[1]c
+}
+
+const getRocket = %[1]cquery GetRocket($id:ID!){rocket(id:$id){id name}}%[1]c
+
+const onRocketLaunched = %[1]csubscription OnRocketLaunched($id:ID!){rocketLaunched(id:$id){name}}%[1]c
+
+type RocketClient interface {
+	GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error)
+	OnRocketLaunched(ctx context.Context, id string, header http.Header) (<-chan OnRocketLaunchedEvent, error)
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	return c.ctrl.Execute(ctx, getRocket, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *rocketClient) OnRocketLaunched(ctx context.Context, id string, header http.Header) (<-chan OnRocketLaunchedEvent, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	msgs, err := c.ctrl.Subscribe(ctx, onRocketLaunched, params, header)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan OnRocketLaunchedEvent)
+	go func() {
+		defer close(events)
+		for msg := range msgs {
+			var event OnRocketLaunchedEvent
+			if event.Err = msg.Err; event.Err == nil {
+				event.Err = GraphqlClient.DecodePayload(msg.Payload, &event)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket Rocket %[1]cjson:"rocket"%[1]c
+}
+
+type OnRocketLaunchedEvent struct {
+	Data   OnRocketLaunchedData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError       %[1]cjson:"errors"%[1]c
+	Err    error                %[1]cjson:"-"%[1]c
+}
+
+type OnRocketLaunchedData struct {
+	RocketLaunched Rocket %[1]cjson:"rocketLaunched"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_SubscriptionOnly(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/subscription/schema.graphql")
+	query := loadQuery(t, schema, "test/subscription/subscription_only.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Rocket struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+const onRocketLaunched = %[1]csubscription OnRocketLaunched($id:ID!){rocketLaunched(id:$id){name}}%[1]c
+
+type RocketClient interface {
+	OnRocketLaunched(ctx context.Context, id string, header http.Header) (<-chan OnRocketLaunchedEvent, error)
+}
+
+func (c *rocketClient) OnRocketLaunched(ctx context.Context, id string, header http.Header) (<-chan OnRocketLaunchedEvent, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	msgs, err := c.ctrl.Subscribe(ctx, onRocketLaunched, params, header)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan OnRocketLaunchedEvent)
+	go func() {
+		defer close(events)
+		for msg := range msgs {
+			var event OnRocketLaunchedEvent
+			if event.Err = msg.Err; event.Err == nil {
+				event.Err = GraphqlClient.DecodePayload(msg.Payload, &event)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+type OnRocketLaunchedEvent struct {
+	Data   OnRocketLaunchedData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError       %[1]cjson:"errors"%[1]c
+	Err    error                %[1]cjson:"-"%[1]c
+}
+
+type OnRocketLaunchedData struct {
+	RocketLaunched Rocket %[1]cjson:"rocketLaunched"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+	assert.Equal(t, expOut, out)
+	typeCheck(t, out)
+}
+
+func TestEvaluator_PersistedQueries(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/subscription/schema.graphql")
+	query := loadQuery(t, schema, "test/subscription/query.graphql")
+	info := AdditionalInfo{
+		PackageName:      "grafik_client",
+		ClientName:       "RocketClient",
+		PersistedQueries: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Rocket struct {
+	Id   string %[1]cjson:"id"%[1]c
+	Name string %[1]cjson:"name"%[1]c
+}
+
+const getRocket = %[1]cquery GetRocket($id:ID!){rocket(id:$id){id name}}%[1]c
+
+const getRocketHash = %[1]c4afc20918700fa6f3f05474e36e978fc49ae2c8e932430abf769137f0e4d343a%[1]c
+
+const onRocketLaunched = %[1]csubscription OnRocketLaunched($id:ID!){rocketLaunched(id:$id){name}}%[1]c
+
+type RocketClient interface {
+	GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error)
+	OnRocketLaunched(ctx context.Context, id string, header http.Header) (<-chan OnRocketLaunchedEvent, error)
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	return c.ctrl.ExecutePersisted(ctx, getRocket, getRocketHash, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *rocketClient) OnRocketLaunched(ctx context.Context, id string, header http.Header) (<-chan OnRocketLaunchedEvent, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	msgs, err := c.ctrl.Subscribe(ctx, onRocketLaunched, params, header)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan OnRocketLaunchedEvent)
+	go func() {
+		defer close(events)
+		for msg := range msgs {
+			var event OnRocketLaunchedEvent
+			if event.Err = msg.Err; event.Err == nil {
+				event.Err = GraphqlClient.DecodePayload(msg.Payload, &event)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket Rocket %[1]cjson:"rocket"%[1]c
+}
+
+type OnRocketLaunchedEvent struct {
+	Data   OnRocketLaunchedData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError       %[1]cjson:"errors"%[1]c
+	Err    error                %[1]cjson:"-"%[1]c
+}
+
+type OnRocketLaunchedData struct {
+	RocketLaunched Rocket %[1]cjson:"rocketLaunched"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_PersistedQueries_Mutation(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/simple_type/schema.graphql")
+	query := loadQuery(t, schema, "test/simple_type/query.graphql")
+	info := AdditionalInfo{
+		PackageName:      "grafik_client",
+		ClientName:       "FileClient",
+		PersistedQueries: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type File struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+const getFileNameWithId = %[1]cquery GetFileNameWithId($id:ID!){getFile(id:$id){name}}%[1]c
+
+const getFileNameWithIdHash = %[1]c034969b27c98c7424a2f92a0a31c10f0fad3560b0ccaacad47ae2087ea1875dc%[1]c
+
+const renameFileWithId = %[1]cmutation RenameFileWithId($id:ID!$name:String!){renameFile(id:$id name:$name){name}}%[1]c
+
+const renameFileWithIdHash = %[1]caf07e8c2faf24ba4761a9e7dd884e71b9de2af326a045c665991db052417a07d%[1]c
+
+type FileClient interface {
+	GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error)
+	GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error)
+	RenameFileWithId(ctx context.Context, id string, name string, header http.Header) (*http.Response, error)
+	RenameFileWithIdResponse(ctx context.Context, id string, name string, header http.Header) (*RenameFileWithIdResponse, error)
+}
+
+func (c *fileClient) GetFileNameWithId(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	return c.ctrl.ExecutePersisted(ctx, getFileNameWithId, getFileNameWithIdHash, params, header)
+}
+
+func (c *fileClient) GetFileNameWithIdResponse(ctx context.Context, id string, header http.Header) (*GetFileNameWithIdResponse, error) {
+	res, err := c.GetFileNameWithId(ctx, id, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetFileNameWithIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *fileClient) RenameFileWithId(ctx context.Context, id string, name string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 2)
+	params["id"] = id
+	params["name"] = name
+
+	return c.ctrl.ExecutePersistedMutation(ctx, renameFileWithId, renameFileWithIdHash, params, header)
+}
+
+func (c *fileClient) RenameFileWithIdResponse(ctx context.Context, id string, name string, header http.Header) (*RenameFileWithIdResponse, error) {
+	res, err := c.RenameFileWithId(ctx, id, name, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out RenameFileWithIdResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetFileNameWithIdResponse struct {
+	Data   GetFileNameWithIdData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError        %[1]cjson:"errors"%[1]c
+}
+
+type GetFileNameWithIdData struct {
+	GetFile File %[1]cjson:"getFile"%[1]c
+}
+
+type RenameFileWithIdResponse struct {
+	Data   RenameFileWithIdData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError       %[1]cjson:"errors"%[1]c
+}
+
+type RenameFileWithIdData struct {
+	RenameFile File %[1]cjson:"renameFile"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type fileClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) FileClient {
+	return &fileClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_MultipleSources(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/multi_file/schema/schema.graphql", "test/multi_file/schema/types/rocket.graphql", "test/multi_file/schema/types/launch.graphql")
+	query := loadQuerySources(t, schema, "test/multi_file/query/rockets.graphql", "test/multi_file/query/launches.graphql", "test/multi_file/query/fragments/rocket_info.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "SpaceXClient",
+		UsePointers: false,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Launch struct {
+	MissionName string %[1]cjson:"mission_name"%[1]c
+	Rocket      Rocket %[1]cjson:"rocket"%[1]c
+}
+
+type Rocket struct {
+	Id      string %[1]cjson:"id"%[1]c
+	Name    string %[1]cjson:"name"%[1]c
+	Country string %[1]cjson:"country"%[1]c
+}
+
+const getRockets = %[1]cquery getRockets($limit:Int){rockets(limit:$limit){...RocketInfo}}fragment RocketInfo on Rocket{id name country}%[1]c
+
+const getLaunches = %[1]cquery getLaunches{launches{mission_name rocket{...RocketInfo}}}fragment RocketInfo on Rocket{id name country}%[1]c
+
+type SpaceXClient interface {
+	GetRockets(ctx context.Context, limit int, header http.Header) (*http.Response, error)
+	GetRocketsResponse(ctx context.Context, limit int, header http.Header) (*GetRocketsResponse, error)
+	GetLaunches(ctx context.Context, header http.Header) (*http.Response, error)
+	GetLaunchesResponse(ctx context.Context, header http.Header) (*GetLaunchesResponse, error)
+}
+
+func (c *spaceXClient) GetRockets(ctx context.Context, limit int, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["limit"] = limit
+
+	return c.ctrl.Execute(ctx, getRockets, params, header)
+}
+
+func (c *spaceXClient) GetRocketsResponse(ctx context.Context, limit int, header http.Header) (*GetRocketsResponse, error) {
+	res, err := c.GetRockets(ctx, limit, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *spaceXClient) GetLaunches(ctx context.Context, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 0)
+
+	return c.ctrl.Execute(ctx, getLaunches, params, header)
+}
+
+func (c *spaceXClient) GetLaunchesResponse(ctx context.Context, header http.Header) (*GetLaunchesResponse, error) {
+	res, err := c.GetLaunches(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetLaunchesResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketsResponse struct {
+	Data   GetRocketsData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketsData struct {
+	Rockets []Rocket %[1]cjson:"rockets"%[1]c
+}
+
+type GetLaunchesResponse struct {
+	Data   GetLaunchesData %[1]cjson:"data"%[1]c
+	Errors []GraphQLError  %[1]cjson:"errors"%[1]c
+}
+
+type GetLaunchesData struct {
+	Launches []Launch %[1]cjson:"launches"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type spaceXClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) SpaceXClient {
+	return &spaceXClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_Plugins(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/optional_inputs/schema.graphql")
+	query := loadQuery(t, schema, "test/optional_inputs/query.graphql")
+	info := AdditionalInfo{
+		PackageName:    "grafik_client",
+		ClientName:     "RocketClient",
+		OptionalInputs: true,
+		Plugins: []plugin.Plugin{
+			plugin.FieldTags("db"),
+			testPlugin{
+				name: "rename",
+				mutate: func(m *plugin.Model) error {
+					for _, s := range m.Types {
+						for i, f := range s.Fields {
+							if s.Name == "RocketInput" && f.Name == "name" {
+								s.Fields[i].Name = "title"
+							}
+						}
+					}
+					m.Operations[0].Args[0].Type = "*RocketInput"
+					m.Types = append(m.Types, &ds.Struct{
+						Name:   "RocketPage",
+						Fields: []ds.TypeField{{Name: "items", Type: "[]Rocket", JsonName: "items"}},
+					})
+					m.Declarations = append(m.Declarations, "func (r Rocket) String() string {\n\treturn r.Id\n}")
+					return nil
+				},
+			},
+		},
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Rocket struct {
+	Id   string %[1]cjson:"id" db:"id"%[1]c
+	Name string %[1]cjson:"name" db:"name"%[1]c
+}
+
+type RocketInput struct {
+	Id         string   %[1]cjson:"id" db:"id"%[1]c
+	Title      *string  %[1]cjson:"name" db:"name"%[1]c
+	Active     *bool    %[1]cjson:"active" db:"active"%[1]c
+	Tags       []string %[1]cjson:"tags" db:"tags"%[1]c
+	NullFields []string %[1]cjson:"-"%[1]c
+}
+
+func (i RocketInput) MarshalJSON() ([]byte, error) {
+	type alias RocketInput
+	unset := map[string]bool{
+		"name":   i.Title == nil,
+		"active": i.Active == nil,
+		"tags":   i.Tags == nil,
+	}
+	return GraphqlClient.MarshalInput(alias(i), unset, i.NullFields)
+}
+
+const updateRocket = %[1]cmutation UpdateRocket($input:RocketInput!){updateRocket(input:$input){id name}}%[1]c
+
+type RocketClient interface {
+	UpdateRocket(ctx context.Context, input *RocketInput, header http.Header) (*http.Response, error)
+	UpdateRocketResponse(ctx context.Context, input *RocketInput, header http.Header) (*UpdateRocketResponse, error)
+}
+
+func (c *rocketClient) UpdateRocket(ctx context.Context, input *RocketInput, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["input"] = input
+
+	return c.ctrl.Execute(ctx, updateRocket, params, header)
+}
+
+func (c *rocketClient) UpdateRocketResponse(ctx context.Context, input *RocketInput, header http.Header) (*UpdateRocketResponse, error) {
+	res, err := c.UpdateRocket(ctx, input, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out UpdateRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type UpdateRocketResponse struct {
+	Data   UpdateRocketData %[1]cjson:"data" db:"data"%[1]c
+	Errors []GraphQLError   %[1]cjson:"errors" db:"errors"%[1]c
+}
+
+type UpdateRocketData struct {
+	UpdateRocket Rocket %[1]cjson:"updateRocket" db:"updateRocket"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+
+type RocketPage struct {
+	Items []Rocket %[1]cjson:"items"%[1]c
+}
+
+func (r Rocket) String() string {
+	return r.Id
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_Plugins_Error(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/optional_inputs/schema.graphql")
+	query := loadQuery(t, schema, "test/optional_inputs/query.graphql")
+	tests := []struct {
+		plugin plugin.Plugin
+		expErr string
+	}{
+		{
+			testPlugin{name: "failing", mutate: func(m *plugin.Model) error {
+				return errors.New("unsupported model")
+			}},
+			"plugin failing failed. Cause: unsupported model",
+		},
+		{
+			testPlugin{name: "removing", mutate: func(m *plugin.Model) error {
+				m.Operations = nil
+				return nil
+			}},
+			"plugin removing failed. Cause: operations cannot be appended or removed",
+		},
+		{
+			testPlugin{name: "renaming", mutate: func(m *plugin.Model) error {
+				m.Operations[0].Name = "changeRocket"
+				return nil
+			}},
+			"plugin renaming failed. Cause: operation UpdateRocket cannot be renamed",
+		},
+		{
+			testPlugin{name: "renaming_response", mutate: func(m *plugin.Model) error {
+				for _, s := range m.Types {
+					if s.Name == "UpdateRocketData" {
+						s.Name = "ChangedRocketData"
+					}
+				}
+				return nil
+			}},
+			"plugin renaming_response failed. Cause: response type UpdateRocketData cannot be renamed or removed",
+		},
+		{
+			testPlugin{name: "removing_response", mutate: func(m *plugin.Model) error {
+				types := m.Types[:0]
+				for _, s := range m.Types {
+					if s.Name != "UpdateRocketResponse" {
+						types = append(types, s)
+					}
+				}
+				m.Types = types
+				return nil
+			}},
+			"plugin removing_response failed. Cause: response type UpdateRocketResponse cannot be renamed or removed",
+		},
+		{
+			plugin.FieldTags("db:"),
+			"plugin field_tags failed. Cause: invalid struct tag key \"db:\"",
+		},
+	}
+
+	for _, test := range tests {
+		e := New(schema, query, AdditionalInfo{
+			PackageName: "grafik_client",
+			ClientName:  "RocketClient",
+			Plugins:     []plugin.Plugin{test.plugin},
+		})
+		out, err := e.Generate()
+		assert.Nil(t, out)
+		assert.EqualError(t, err, test.expErr)
+	}
+}
+
+func TestEvaluator_ConditionalFields(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/conditional_fields/schema.graphql")
+	query := loadQuery(t, schema, "test/conditional_fields/query.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Company struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type Engine struct {
+	Number int %[1]cjson:"number"%[1]c
+}
+
+type Rocket struct {
+	Id          string   %[1]cjson:"id"%[1]c
+	Name        *string  %[1]cjson:"name"%[1]c
+	Country     *string  %[1]cjson:"country"%[1]c
+	Description *string  %[1]cjson:"description"%[1]c
+	Engines     []Engine %[1]cjson:"engines"%[1]c
+	Company     *Company %[1]cjson:"company"%[1]c
+}
+
+const getRocket = %[1]cquery getRocket($id:ID!$withDetails:Boolean!$skipEngines:Boolean=false){rocket(id:$id){id id@include(if:$withDetails)name@include(if:$withDetails)country@include(if:$withDetails)engines@skip(if:$skipEngines){number}...on Rocket@include(if:$withDetails){company{name}}...RocketDescription@skip(if:$skipEngines)}rocketCount@include(if:$withDetails)}fragment RocketDescription on Rocket{description}%[1]c
+
+type RocketClient interface {
+	GetRocket(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*GetRocketResponse, error)
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 3)
+	params["id"] = id
+	params["withDetails"] = withDetails
+	params["skipEngines"] = skipEngines
+
+	return c.ctrl.Execute(ctx, getRocket, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, withDetails, skipEngines, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket      Rocket %[1]cjson:"rocket"%[1]c
+	RocketCount *int   %[1]cjson:"rocketCount"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_ConditionalFields_PerOperationTypes(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/conditional_fields/schema.graphql")
+	query := loadQuery(t, schema, "test/conditional_fields/query.graphql")
+	info := AdditionalInfo{
+		PackageName:       "grafik_client",
+		ClientName:        "RocketClient",
+		PerOperationTypes: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+const getRocket = %[1]cquery getRocket($id:ID!$withDetails:Boolean!$skipEngines:Boolean=false){rocket(id:$id){id id@include(if:$withDetails)name@include(if:$withDetails)country@include(if:$withDetails)engines@skip(if:$skipEngines){number}...on Rocket@include(if:$withDetails){company{name}}...RocketDescription@skip(if:$skipEngines)}rocketCount@include(if:$withDetails)}fragment RocketDescription on Rocket{description}%[1]c
+
+type RocketClient interface {
+	GetRocket(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*GetRocketResponse, error)
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 3)
+	params["id"] = id
+	params["withDetails"] = withDetails
+	params["skipEngines"] = skipEngines
+
+	return c.ctrl.Execute(ctx, getRocket, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, withDetails, skipEngines, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket      GetRocketRocket %[1]cjson:"rocket"%[1]c
+	RocketCount *int            %[1]cjson:"rocketCount"%[1]c
+}
+
+type GetRocketRocket struct {
+	Id          string                   %[1]cjson:"id"%[1]c
+	Name        *string                  %[1]cjson:"name"%[1]c
+	Country     *string                  %[1]cjson:"country"%[1]c
+	Engines     []GetRocketRocketEngines %[1]cjson:"engines"%[1]c
+	Company     *GetRocketRocketCompany  %[1]cjson:"company"%[1]c
+	Description *string                  %[1]cjson:"description"%[1]c
+}
+
+type GetRocketRocketEngines struct {
+	Number int %[1]cjson:"number"%[1]c
+}
+
+type GetRocketRocketCompany struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+	assert.Equal(t, expOut, out)
+}
+
+func TestEvaluator_ConditionalFields_FragmentStructs(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/conditional_fields/schema.graphql")
+	query := loadQuery(t, schema, "test/conditional_fields/query.graphql")
+	info := AdditionalInfo{
+		PackageName:     "grafik_client",
+		ClientName:      "RocketClient",
+		FragmentStructs: true,
+	}
+	e := New(schema, query, info)
+
+	out := getSourceString(t, e)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+// Generated with grafik. DO NOT EDIT
+
+package grafik_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+type Company struct {
+	Name string %[1]cjson:"name"%[1]c
+}
+
+type Engine struct {
+	Number int %[1]cjson:"number"%[1]c
+}
+
+type Rocket struct {
+	RocketDescription %[1]cjson:"-"%[1]c
+	Id                string   %[1]cjson:"id"%[1]c
+	Name              *string  %[1]cjson:"name"%[1]c
+	Country           *string  %[1]cjson:"country"%[1]c
+	Engines           []Engine %[1]cjson:"engines"%[1]c
+	Company           *Company %[1]cjson:"company"%[1]c
+}
+
+func (s *Rocket) UnmarshalJSON(data []byte) error {
+	type alias Rocket
+	if err := json.Unmarshal(data, (*alias)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketDescription); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s Rocket) MarshalJSON() ([]byte, error) {
+	type alias Rocket
+	return GraphqlClient.MarshalFragments(alias(s), s.RocketDescription)
+}
+
+type RocketDescription struct {
+	Description *string %[1]cjson:"description"%[1]c
+}
+
+const getRocket = %[1]cquery getRocket($id:ID!$withDetails:Boolean!$skipEngines:Boolean=false){rocket(id:$id){id id@include(if:$withDetails)name@include(if:$withDetails)country@include(if:$withDetails)engines@skip(if:$skipEngines){number}...on Rocket@include(if:$withDetails){company{name}}...RocketDescription@skip(if:$skipEngines)}rocketCount@include(if:$withDetails)}fragment RocketDescription on Rocket{description}%[1]c
+
+type RocketClient interface {
+	GetRocket(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*http.Response, error)
+	GetRocketResponse(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*GetRocketResponse, error)
+}
+
+func (c *rocketClient) GetRocket(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 3)
+	params["id"] = id
+	params["withDetails"] = withDetails
+	params["skipEngines"] = skipEngines
+
+	return c.ctrl.Execute(ctx, getRocket, params, header)
+}
+
+func (c *rocketClient) GetRocketResponse(ctx context.Context, id string, withDetails bool, skipEngines bool, header http.Header) (*GetRocketResponse, error) {
+	res, err := c.GetRocket(ctx, id, withDetails, skipEngines, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+type GetRocketResponse struct {
+	Data   GetRocketData  %[1]cjson:"data"%[1]c
+	Errors []GraphQLError %[1]cjson:"errors"%[1]c
+}
+
+type GetRocketData struct {
+	Rocket      Rocket %[1]cjson:"rocket"%[1]c
+	RocketCount *int   %[1]cjson:"rocketCount"%[1]c
+}
+
+type GraphQLError struct {
+	Message    string                 %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation %[1]cjson:"locations"%[1]c
+	Extensions GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code string %[1]cjson:"code"%[1]c
+}
+
+type rocketClient struct {
+	ctrl GraphqlClient.Client
+}
+
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) RocketClient {
+	return &rocketClient{
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
+	}
+}
+`, '`'))
+	assert.Equal(t, expOut, out)
+}
+
+// testPlugin is a plugin modifying the model with mutate function.
+type testPlugin struct {
+	name   string
+	mutate func(m *plugin.Model) error
+}
+
+func (p testPlugin) Name() string {
+	return p.name
+}
+
+func (p testPlugin) Mutate(m *plugin.Model) error {
+	return p.mutate(m)
+}
+
+func loadSchema(t *testing.T, schemaNames ...string) *ast.Schema {
+	sources := make([]*ast.Source, len(schemaNames))
+	for i, schemaName := range schemaNames {
+		schemaLoc := path.Join("../", schemaName)
+		file, err := ioutil.ReadFile(schemaLoc)
+		assert.NoError(t, err)
+		assert.NotNil(t, file)
+
+		sources[i] = &ast.Source{
+			Input: string(file),
+			Name:  path.Base(schemaName),
+		}
+	}
+
+	return gqlparser.MustLoadSchema(sources...)
+}
+
+func loadQuery(t *testing.T, schema *ast.Schema, queryName string) *ast.QueryDocument {
+	queryLoc := path.Join("../", queryName)
+	file, err := ioutil.ReadFile(queryLoc)
+	assert.NoError(t, err)
+	assert.NotNil(t, file)
+
+	return gqlparser.MustLoadQuery(schema, string(file))
+}
+
+func loadQuerySources(t *testing.T, schema *ast.Schema, queryNames ...string) *ast.QueryDocument {
+	query := &ast.QueryDocument{}
+	for _, queryName := range queryNames {
+		queryLoc := path.Join("../", queryName)
+		file, err := ioutil.ReadFile(queryLoc)
+		assert.NoError(t, err)
+
+		doc, gqlErr := parser.ParseQuery(&ast.Source{Input: string(file), Name: path.Base(queryName)})
+		assert.Nil(t, gqlErr)
+		query.Operations = append(query.Operations, doc.Operations...)
+		query.Fragments = append(query.Fragments, doc.Fragments...)
+	}
+	assert.Nil(t, validator.Validate(schema, query))
+
+	return query
+}
+
+func getSourceString(t *testing.T, e Evaluator) string {
+	fileContent, err := e.Generate()
+	assert.NoError(t, err)
+	src := &bytes.Buffer{}
+	_, err = fileContent.WriteTo(src)
+	assert.NoError(t, err)
+
+	return src.String()
+}
+
+// typeCheck checks that generated client compiles.
+func typeCheck(t *testing.T, src string) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "client.go", src, 0)
+	assert.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("grafik_client", fset, []*goast.File{file}, nil)
+	assert.NoError(t, err)
 }
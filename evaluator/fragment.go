@@ -0,0 +1,211 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/common"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/vektah/gqlparser/ast"
+	"sort"
+	"strings"
+)
+
+// genFragmentStructs generates struct of each named fragment used by operations if AdditionalInfo.FragmentStructs is set.
+// Fragment struct contains fields selected by the fragment and embeds structs of fragments spread in it.
+// Nested structs of fragment are named after the path from the fragment if AdditionalInfo.PerOperationTypes is set.
+func (e *evaluator) genFragmentStructs() {
+	if !e.AdditionalInfo.FragmentStructs {
+		return
+	}
+	conditional := e.conditionalFragments()
+	for _, f := range e.usedFragmentDefinitions() {
+		e.position = f.Position
+		name := fragmentStructName(f.Name)
+		if _, ok := e.schema.Types[name]; ok {
+			e.report(fmt.Errorf("struct of fragment %s conflicts with GraphQL type %s", f.Name, name))
+			continue
+		}
+		fragments := e.spreadFragments([]ast.SelectionSet{f.SelectionSet}, matchAnyType)
+		// All fields of fragment spread conditionally may be omitted from the response.
+		fields := collectSelections(f.SelectionSet, make([]*selectedField, 0), matchAnyType, false, conditional[f.Name])
+
+		if e.AdditionalInfo.PerOperationTypes {
+			typeFields, structs, unions := e.parseOperationFields(name, fields, fragments)
+			if s, ok := e.modelStruct(ds.Struct{Name: name, Fields: typeFields}); ok {
+				e.report(e.generator.WriteLineBreak(twoLinesBreak))
+				e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+				e.report(e.generator.WriteLineBreak(twoLinesBreak))
+			}
+			e.genOperationStructs(structs, unions)
+			continue
+		}
+
+		def := e.schema.Types[f.TypeCondition]
+		defFields := def.Fields
+		if isAbstractKind(def.Kind) {
+			defFields = e.commonFields(def)
+		}
+		s, ok := e.modelStruct(ds.Struct{
+			Name:   name,
+			Fields: append(embeddedFields(fragments), e.parseFieldArgs(&defFields, fieldNames(fields), conditionalNames(fields))...),
+		})
+		if !ok {
+			continue
+		}
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+		e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+	}
+	e.position = nil
+}
+
+// fragmentSelection returns names of fields selected directly on the type and fragments spread on it in all selection sets of the type.
+// The result is returned only if AdditionalInfo.FragmentStructs is set and any fragment is spread on the type - otherwise fields selected on the type are not changed.
+func (e *evaluator) fragmentSelection(typeName string, matches func(typeCondition string) bool) ([]string, []*ast.FragmentDefinition, bool) {
+	if !e.AdditionalInfo.FragmentStructs {
+		return nil, nil, false
+	}
+	sets := e.typeSelectionSets(typeName)
+	fragments := e.spreadFragments(sets, matches)
+	if len(fragments) == 0 {
+		return nil, nil, false
+	}
+	fields := make([]*selectedField, 0)
+	for _, set := range sets {
+		fields = collectMatchingFields(set, fields, matches, false)
+	}
+	return fieldNames(fields), fragments, true
+}
+
+// spreadFragments returns fragments spread in selection sets (including inline fragments) if AdditionalInfo.FragmentStructs is set.
+// Fragments spread in other fragments are not returned - they are embedded in the struct of the fragment spreading them.
+func (e *evaluator) spreadFragments(sets []ast.SelectionSet, matches func(typeCondition string) bool) []*ast.FragmentDefinition {
+	if !e.AdditionalInfo.FragmentStructs {
+		return nil
+	}
+	fragments := make([]*ast.FragmentDefinition, 0)
+	for _, set := range sets {
+		fragments = collectSpreads(set, matches, fragments)
+	}
+	return fragments
+}
+
+// collectSpreads appends fragments spread in the selection set to fragments. Each fragment is appended once.
+func collectSpreads(set ast.SelectionSet, matches func(typeCondition string) bool, fragments []*ast.FragmentDefinition) []*ast.FragmentDefinition {
+	for _, selection := range set {
+		switch selectionType := selection.(type) {
+		case *ast.InlineFragment:
+			if matches(selectionType.TypeCondition) {
+				fragments = collectSpreads(selectionType.SelectionSet, matches, fragments)
+			}
+		case *ast.FragmentSpread:
+			if !matches(selectionType.Definition.TypeCondition) || containsFragment(fragments, selectionType.Definition) {
+				continue
+			}
+			fragments = append(fragments, selectionType.Definition)
+		}
+	}
+	return fragments
+}
+
+// containsFragment checks if fragments contain the fragment.
+func containsFragment(fragments []*ast.FragmentDefinition, f *ast.FragmentDefinition) bool {
+	for _, fragment := range fragments {
+		if fragment.Name == f.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// embeddedFields returns embedded struct fields of fragments.
+func embeddedFields(fragments []*ast.FragmentDefinition) []ds.TypeField {
+	fields := make([]ds.TypeField, len(fragments))
+	for i, f := range fragments {
+		name := fragmentStructName(f.Name)
+		fields[i] = ds.TypeField{
+			Name:     name,
+			Type:     name,
+			Embedded: true,
+		}
+	}
+	return fields
+}
+
+// fragmentStructName returns the name of the struct of named fragment - i.e. RocketSummary.
+func fragmentStructName(name string) string {
+	return common.SnakeCaseToCamelCase(strings.Title(name))
+}
+
+// matchAnyType is used to collect selections of fragments regardless of their type condition.
+func matchAnyType(string) bool {
+	return true
+}
+
+// fieldNames returns names of GraphQL fields of collected fields.
+func fieldNames(fields []*selectedField) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.field.Name
+	}
+	return names
+}
+
+// genEmbeddedMarshallers generates UnmarshalJSON and MarshalJSON functions of struct embedding fragment structs.
+// Fragment structs never get them, so the functions of embedded struct are never promoted to the struct embedding it.
+// Embedded fragment structs are omitted from JSON of the struct and decoded separately, so fields selected by multiple fragments are set in all of them.
+// They are encoded separately as well and merged into single JSON object.
+func (e *evaluator) genEmbeddedMarshallers(s ds.Struct) {
+	paths := make([]string, 0)
+	for _, f := range s.Fields {
+		if f.Embedded {
+			paths = append(paths, e.fragmentPaths(f.Type)...)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WriteEmbeddedUnmarshaller(s, paths))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WriteEmbeddedMarshaller(s, paths))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+}
+
+// fragmentPaths returns selector of embedded fragment struct followed by selectors of all fragment structs embedded in it.
+// I.e. RocketInfo, RocketInfo.RocketIds.
+func (e *evaluator) fragmentPaths(structName string) []string {
+	paths := []string{structName}
+	for _, f := range e.usedFragmentDefinitions() {
+		if fragmentStructName(f.Name) != structName {
+			continue
+		}
+		for _, nested := range e.spreadFragments([]ast.SelectionSet{f.SelectionSet}, matchAnyType) {
+			for _, path := range e.fragmentPaths(fragmentStructName(nested.Name)) {
+				paths = append(paths, fmt.Sprintf("%s.%s", structName, path))
+			}
+		}
+	}
+	return paths
+}
+
+// usedFragmentDefinitions returns all fragments used by operations sorted by name.
+func (e *evaluator) usedFragmentDefinitions() []*ast.FragmentDefinition {
+	visited := make(map[string]bool)
+	fragments := make([]*ast.FragmentDefinition, 0)
+	for _, op := range e.queryDocument.Operations {
+		fragments = append(fragments, e.usedFragments(op.SelectionSet, visited)...)
+	}
+	sort.Slice(fragments, func(i, j int) bool {
+		return fragments[i].Name < fragments[j].Name
+	})
+	return fragments
+}
+
+// fragmentImports returns imports required by generated decoding of fragment structs.
+func (e *evaluator) fragmentImports() []ds.Import {
+	if !e.AdditionalInfo.FragmentStructs || len(e.usedFragmentDefinitions()) == 0 {
+		return nil
+	}
+	return []ds.Import{{Path: "encoding/json"}}
+}
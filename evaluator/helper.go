@@ -3,9 +3,9 @@
 package evaluator
 
 import (
-	"bufio"
-	"bytes"
+	"errors"
 	"fmt"
+	"github.com/Bartosz-D3V/grafik/client"
 	"github.com/Bartosz-D3V/grafik/common"
 	"github.com/Bartosz-D3V/grafik/ds"
 	"github.com/Bartosz-D3V/grafik/generator"
@@ -23,16 +23,17 @@ const (
 
 // genSchemaDef generates custom, user-defined structs and enums used in GraphQL query file.
 func (e *evaluator) genSchemaDef() {
-	e.generator.WriteLineBreak(twoLinesBreak)
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 
 	e.generateGoTypes()
+	e.genFragmentStructs()
 
-	e.generator.WriteLineBreak(twoLinesBreak)
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 }
 
 // generateGoTypes iterates through all fields in GraphQL query and generates GO type based on selected subfields.
 func (e *evaluator) generateGoTypes() {
-	cTypes := e.visitor.IntrospectTypes()
+	cTypes := e.customTypes
 
 	// To make the output order of the generated code deterministic always sort alphabetically.
 	keys := make([]string, 0, len(cTypes))
@@ -44,23 +45,37 @@ func (e *evaluator) generateGoTypes() {
 	for _, key := range keys {
 		cType, ok := e.schema.Types[key]
 		if !ok {
-			panic(fmt.Errorf("failed to find definition of %s in GraphQL Schema AST", key))
+			e.reportAt(nil, fmt.Errorf("failed to find definition of %s in GraphQL Schema AST", key))
+			continue
+		}
+		e.position = cType.Position
+
+		// Output types are generated separately for each operation.
+		if e.AdditionalInfo.PerOperationTypes && isOutputKind(cType.Kind) {
+			continue
 		}
 
 		switch cType.Kind {
-		case ast.Object,
-			ast.InputObject:
+		case ast.Object:
 			e.createStruct(cType, cTypes[key])
+		case ast.InputObject:
+			e.createInputStruct(cType, cTypes[key])
 		case ast.Enum:
 			e.createEnum(cType)
 		case ast.Scalar:
 			e.createInterfaceType(cType)
 		case ast.Interface:
-			e.createCommonStruct(cType, cTypes[key], graphQLFragmentStructName)
+			e.createAbstractType(cType, cTypes[key], graphQLFragmentStructName)
 		case ast.Union:
-			e.createCommonStruct(cType, cTypes[key], graphQLUnionStructName)
+			e.createAbstractType(cType, cTypes[key], graphQLUnionStructName)
 		}
 	}
+	e.position = nil
+}
+
+// isOutputKind returns true if GraphQL type of given kind can only be used in selection sets.
+func isOutputKind(kind ast.DefinitionKind) bool {
+	return kind == ast.Object || kind == ast.Interface || kind == ast.Union
 }
 
 // createEnum creates generator.Enum and writes to IO.
@@ -70,34 +85,121 @@ func (e *evaluator) createEnum(cType *ast.Definition) {
 		fields[i] = field.Name
 	}
 
-	en := ds.Enum{
+	en, ok := e.modelEnum(ds.Enum{
 		Name:   cType.Name,
 		Fields: fields,
+	})
+	if !ok {
+		return
 	}
 
-	e.generator.WriteLineBreak(twoLinesBreak)
-	e.generator.WriteEnum(en)
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WriteEnum(en))
 }
 
 // createInterface creates type 'any' in Go [type X interface{}] and writes to IO.
+// If the scalar is bound to Go type, type alias is created instead [type X = time.Time].
 func (e *evaluator) createInterfaceType(cType *ast.Definition) {
-	e.generator.WriteLineBreak(twoLinesBreak)
-	e.generator.WriteInterface(cType.Name)
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	if goType, ok := e.scalarBinding(cType.Name); ok {
+		e.report(e.generator.WriteTypeAlias(cType.Name, goType.Type))
+		return
+	}
+	e.report(e.generator.WriteInterface(cType.Name))
 }
 
 // createStruct creates generator.Struct and writes to IO.
+// If AdditionalInfo.FragmentStructs is set, structs of fragments spread on the type are embedded instead of their fields.
 func (e *evaluator) createStruct(cType *ast.Definition, selectedFields []string) {
-	s := ds.Struct{
+	var fragments []*ast.FragmentDefinition
+	if names, spread, ok := e.fragmentSelection(cType.Name, e.possibleTypeMatcher(cType)); ok {
+		selectedFields, fragments = names, spread
+	}
+	e.writeStruct(ds.Struct{
 		Name:   cType.Name,
-		Fields: e.parseFieldArgs(&cType.Fields, selectedFields),
+		Fields: append(embeddedFields(fragments), e.parseFieldArgs(&cType.Fields, selectedFields, e.typeConditionalFields(cType.Name, e.possibleTypeMatcher(cType)))...),
+	})
+}
+
+// writeStruct writes public struct modified by plugins to IO together with UnmarshalJSON function if it embeds fragment structs.
+func (e *evaluator) writeStruct(s ds.Struct) {
+	s, ok := e.modelStruct(s)
+	if !ok {
+		return
+	}
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+	e.genEmbeddedMarshallers(s)
+}
+
+// createInputStruct creates generator.Struct for GraphQL input object and writes to IO.
+// If AdditionalInfo.OptionalInputs is set, nullable fields are generated as pointers and omitted from the request when nil.
+// Fields listed in NullFields are sent as explicit null.
+func (e *evaluator) createInputStruct(cType *ast.Definition, selectedFields []string) {
+	if !e.AdditionalInfo.OptionalInputs {
+		e.createStruct(cType, selectedFields)
+		return
+	}
+
+	fields := e.parseFieldArgs(&cType.Fields, selectedFields, nil)
+	optional := make(map[string]bool)
+	for i, field := range fields {
+		if !field.NonNull {
+			fields[i] = field.PointerType()
+			optional[field.JsonName] = true
+		}
+	}
+	fields = append(fields, ds.TypeField{
+		Name:     generator.NullFieldsName,
+		Type:     "[]string",
+		JsonName: "-",
+	})
+
+	s, ok := e.modelStruct(ds.Struct{
+		Name:   cType.Name,
+		Fields: fields,
+	})
+	if !ok {
+		return
+	}
+	// Optional fields are taken from the struct modified by plugins, so renamed fields are marshalled correctly.
+	optionalFields := make([]ds.TypeField, 0)
+	for _, field := range s.Fields {
+		if optional[field.JsonName] {
+			optionalFields = append(optionalFields, field)
+		}
+	}
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WriteInputMarshaller(s, optionalFields))
+}
+
+// createAbstractType creates Go type of GraphQL interface or union.
+// If AdditionalInfo.DiscriminatedUnions is set, Go interface with concrete struct per possible type is created instead of the common struct.
+func (e *evaluator) createAbstractType(cType *ast.Definition, selectedFields []string, graphQLTypeSuffix string) {
+	if e.AdditionalInfo.DiscriminatedUnions {
+		e.createUnion(cType, graphQLTypeSuffix)
+		return
 	}
-	e.generator.WriteLineBreak(twoLinesBreak)
-	e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers)
+	e.createCommonStruct(cType, selectedFields, graphQLTypeSuffix)
 }
 
 // createCommonStruct creates a generic struct containing all the fields that interface and all implementations it has.
 func (e *evaluator) createCommonStruct(cType *ast.Definition, selectedFields []string, graphQLTypeSuffix string) {
-	fragmentName := fmt.Sprintf("%s%s", cType.Name, graphQLTypeSuffix)
+	var fragments []*ast.FragmentDefinition
+	if names, spread, ok := e.fragmentSelection(cType.Name, matchAnyType); ok {
+		selectedFields, fragments = names, spread
+	}
+	fList := e.commonFields(cType)
+	e.writeStruct(ds.Struct{
+		Name:   fmt.Sprintf("%s%s", cType.Name, graphQLTypeSuffix),
+		Fields: append(embeddedFields(fragments), e.parseFieldArgs(&fList, selectedFields, e.typeConditionalFields(cType.Name, matchAnyType))...),
+	})
+}
+
+// commonFields returns fields of all implementations of interface or members of union followed by fields of an interface - each field is returned once.
+func (e *evaluator) commonFields(cType *ast.Definition) ast.FieldList {
 	fragmentFields := make(ast.FieldList, 0)
 
 	// Add fields of all implementations.
@@ -110,57 +212,46 @@ func (e *evaluator) createCommonStruct(cType *ast.Definition, selectedFields []s
 
 	fList := make(ast.FieldList, 0)
 	for _, fField := range fragmentFields {
-		selected := false
-		for _, field := range selectedFields {
-			if fField.Name == field {
-				selected = true
-			}
-		}
-		if !selected {
-			continue
-		}
-
 		if fList.ForName(fField.Name) == nil {
 			fList = append(fList, fField)
 		}
 	}
-
-	fragmentDef := &ast.Definition{
-		Kind:   ast.Object,
-		Name:   fragmentName,
-		Fields: fList,
-	}
-
-	allFields := make([]string, len(fList))
-	for i, field := range fList {
-		allFields[i] = field.Name
-	}
-
-	e.createStruct(fragmentDef, allFields)
+	return fList
 }
 
 // parseSelectionSet creates array of type generator.TypeArg based on selection set.
 // Consider this GraphQL query:
-// query getContinentsAndCountries {
-//    continents {
-//        code
-//    }
-//    country {
-//        name
-//    }
-// }
+//
+//	query getContinentsAndCountries {
+//	   continents {
+//	       code
+//	   }
+//	   country {
+//	       name
+//	   }
+//	}
+//
 // ast.SelectionSet is array of continents and country.
 // parseSelectionSet will return array of type generator.TypeArg with two elements - continents and country.
 // Name will be continents and country. Type will be introspected and either primitive or user defined struct.
 func (e *evaluator) parseSelectionSet(set ast.SelectionSet) []ds.TypeField {
-	selectionSet := make([]ds.TypeField, len(set))
-	for i, s := range set {
-		astField := s.(*ast.Field)
-		selectionSet[i] = ds.TypeField{
+	selectionSet := make([]ds.TypeField, 0, len(set))
+	for _, s := range set {
+		if spread, ok := s.(*ast.FragmentSpread); ok && e.AdditionalInfo.FragmentStructs {
+			selectionSet = append(selectionSet, embeddedFields([]*ast.FragmentDefinition{spread.Definition})...)
+			continue
+		}
+		astField, ok := s.(*ast.Field)
+		if !ok {
+			e.reportAt(s.GetPosition(), errors.New("fragments selected directly in the operation are supported only with per operation types"))
+			continue
+		}
+		selectionSet = append(selectionSet, optionalField(ds.TypeField{
 			Name:     astField.Alias,
-			Type:     e.convGoType(astField.Definition.Type),
+			Type:     e.convFieldType(astField.Definition.Type),
 			JsonName: common.SentenceCase(astField.Alias),
-		}
+			NonNull:  astField.Definition.Type.NonNull,
+		}, isConditional(astField.Directives)))
 	}
 	return selectionSet
 }
@@ -179,7 +270,8 @@ func (e *evaluator) parseFnArgs(args *ast.VariableDefinitionList) []ds.TypeArg {
 }
 
 // parseFieldArgs converts GraphQL fields (ast.FieldList) into generator.TypeArg.
-func (e *evaluator) parseFieldArgs(args *ast.FieldList, selectedFields []string) []ds.TypeField {
+// Fields listed in conditional may be omitted from the response because of @include or @skip directive and are converted to pointers.
+func (e *evaluator) parseFieldArgs(args *ast.FieldList, selectedFields []string, conditional map[string]bool) []ds.TypeField {
 	funcArgs := make([]ds.TypeField, 0)
 	for _, arg := range *args {
 		selected := false
@@ -194,10 +286,11 @@ func (e *evaluator) parseFieldArgs(args *ast.FieldList, selectedFields []string)
 
 		fArg := ds.TypeField{
 			Name:     arg.Name,
-			Type:     e.convGoType(arg.Type),
+			Type:     e.convFieldType(arg.Type),
 			JsonName: common.SentenceCase(arg.Name),
+			NonNull:  arg.Type.NonNull,
 		}
-		funcArgs = append(funcArgs, fArg)
+		funcArgs = append(funcArgs, optionalField(fArg, conditional[arg.Name]))
 	}
 	for k, v := range e.SpecialGraphqlTypesMapping {
 		selected := false
@@ -214,8 +307,9 @@ func (e *evaluator) parseFieldArgs(args *ast.FieldList, selectedFields []string)
 			Name:     v,
 			Type:     e.mapSpecialType(k),
 			JsonName: k,
+			NonNull:  true,
 		}
-		funcArgs = append(funcArgs, fArg)
+		funcArgs = append(funcArgs, optionalField(fArg, conditional[k]))
 	}
 	return funcArgs
 }
@@ -225,7 +319,8 @@ func (e *evaluator) mapSpecialType(name string) string {
 	case "__typename":
 		return "string"
 	default:
-		panic(fmt.Sprintf("Unrecognized field=%s", name))
+		e.report(fmt.Errorf("unrecognized field %s", name))
+		return "interface{}"
 	}
 }
 
@@ -239,7 +334,7 @@ func (e *evaluator) convGoType(astType *ast.Type) string {
 	case "ID":
 		return "string"
 	case "Float":
-		return "int"
+		return "float64"
 	case "Boolean":
 		return "bool"
 	default:
@@ -247,6 +342,15 @@ func (e *evaluator) convGoType(astType *ast.Type) string {
 	}
 }
 
+// convFieldType maps GraphQL type of struct field into Go type.
+// If AdditionalInfo.NullablePointers is set nullable types (including list elements) are returned as pointers.
+func (e *evaluator) convFieldType(astType *ast.Type) string {
+	if !e.AdditionalInfo.NullablePointers {
+		return e.convGoType(astType)
+	}
+	return e.convNamedType(astType, e.convGoType(common.LeafType(astType)))
+}
+
 // convComplexType recursively checks GraphQL type and returns corresponding Go type.
 func (e *evaluator) convComplexType(astType *ast.Type) string {
 	switch {
@@ -285,103 +389,191 @@ func (e *evaluator) convListType(astType *ast.Type) string {
 
 // genOperations generates GraphQL operations as constants.
 // For example the following query:
-// query getContinentsAndCountries {
-//    continents {
-//        code
-//    }
-//    country {
-//        name
-//    }
-// }
+//
+//	query getContinentsAndCountries {
+//	   continents {
+//	       code
+//	   }
+//	   country {
+//	       name
+//	   }
+//	}
+//
 // Will be conversed to this Go code:
-// const getContinentsAndCountries = `query getContinentsAndCountries {
-//    continents {
-//        code
-//    }
-//    country {
-//        name
-//    }
-// }'
+//
+//	const getContinentsAndCountries = `query getContinentsAndCountries{continents{code}country{name}}`
 func (e *evaluator) genOperations() {
 	ops := e.queryDocument.Operations
-	opsCount := len(ops)
-	src := ops[0].Position.Src.Input
+	queries := e.splitOperations()
 
-	var curOp *ast.OperationDefinition
-	var nextOp *ast.OperationDefinition
-
-	// Split multiple GraphQL operations into sub-operations to generate const value for each operation.
-	for i := 0; i < opsCount; i++ {
-		curOp = ops[i]
-		if i < opsCount-1 {
-			nextOp = ops[i+1]
-		} else {
-			nextOp = nil
+	for i, op := range ops {
+		e.position = op.Position
+		c := ds.Const{
+			Name: op.Name,
+			Val:  queries[i],
 		}
-		if nextOp != nil {
-			queryStr := src[curOp.Position.Start:nextOp.Position.Start]
-			queryStr = e.removeComments(queryStr)
-			c := ds.Const{
-				Name: curOp.Name,
-				Val:  queryStr,
-			}
-			e.generator.WriteConst(c)
-			e.generator.WriteLineBreak(twoLinesBreak)
+		e.report(e.generator.WriteConst(c))
+		e.genQueryHash(op, queries[i])
+		if i < len(ops)-1 {
+			e.report(e.generator.WriteLineBreak(twoLinesBreak))
 		} else {
-			queryStr := src[curOp.Position.Start:]
-			queryStr = e.removeComments(queryStr)
-			c := ds.Const{
-				Name: curOp.Name,
-				Val:  queryStr,
+			e.report(e.generator.WriteLineBreak(oneLineBreak))
+		}
+	}
+	e.position = nil
+}
+
+// operationImports returns imports required by generated methods of operations.
+// Only methods decoding response of query or mutation use errors package - subscriptions do not.
+func (e *evaluator) operationImports() []ds.Import {
+	for _, op := range e.queryDocument.Operations {
+		if op.Operation != ast.Subscription {
+			return []ds.Import{{Path: "errors"}}
+		}
+	}
+	return nil
+}
+
+// splitOperations prints each GraphQL operation as self-contained document in canonical, minified form.
+// The query of each operation is returned in the same order as operations of query document.
+// The operation is followed by exactly the fragments it uses (transitively) in the order of their first use, regardless of the source they are defined in.
+func (e *evaluator) splitOperations() []string {
+	ops := e.queryDocument.Operations
+
+	queries := make([]string, len(ops))
+	for i, op := range ops {
+		doc := &ast.QueryDocument{
+			Operations: ast.OperationList{e.printedOperation(op)},
+		}
+		for _, f := range e.usedFragments(op.SelectionSet, map[string]bool{}) {
+			doc.Fragments = append(doc.Fragments, e.printedFragment(f))
+		}
+		queries[i] = printQueryDocument(doc)
+	}
+	return queries
+}
+
+// usedFragments returns all fragments used by the selection set, including fragments used by other fragments.
+func (e *evaluator) usedFragments(set ast.SelectionSet, visited map[string]bool) []*ast.FragmentDefinition {
+	fragments := make([]*ast.FragmentDefinition, 0)
+	for _, sel := range set {
+		switch s := sel.(type) {
+		case *ast.Field:
+			fragments = append(fragments, e.usedFragments(s.SelectionSet, visited)...)
+		case *ast.InlineFragment:
+			fragments = append(fragments, e.usedFragments(s.SelectionSet, visited)...)
+		case *ast.FragmentSpread:
+			f := e.queryDocument.Fragments.ForName(s.Name)
+			if f == nil || visited[f.Name] {
+				continue
 			}
-			e.generator.WriteConst(c)
-			e.generator.WriteLineBreak(oneLineBreak)
+			visited[f.Name] = true
+			fragments = append(fragments, f)
+			fragments = append(fragments, e.usedFragments(f.SelectionSet, visited)...)
 		}
 	}
+	return fragments
+}
+
+// genQueryHash generates const value with precomputed hash of the operation used as automatic persisted query.
+// Hash is generated only if AdditionalInfo.PersistedQueries is set. Subscriptions are never sent as persisted queries.
+func (e *evaluator) genQueryHash(op *ast.OperationDefinition, queryStr string) {
+	if !e.AdditionalInfo.PersistedQueries || op.Operation == ast.Subscription {
+		return
+	}
+	c := ds.Const{
+		Name: fmt.Sprintf("%sHash", op.Name),
+		Val:  client.PersistedQueryHash(queryStr),
+	}
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WriteConst(c))
 }
 
 // genClientCode generates client code - all interfaces, constructor methods and client struct.
 func (e *evaluator) genClientCode() {
 	e.genOpsInterface()
-	e.generator.WriteLineBreak(twoLinesBreak)
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 
 	e.genClientStruct()
-	e.generator.WriteLineBreak(twoLinesBreak)
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
 
-	e.generator.WriteClientConstructor(e.AdditionalInfo.ClientName)
+	e.report(e.generator.WriteClientConstructor(e.AdditionalInfo.ClientName))
 }
 
 // genOpsInterface generates public interface for grafik client.
 // For example:
-// type SpaceXClient interface {
-//	AddOrUpdateHardcodedUser(rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*http.Response, error)
-// }
+//
+//	type SpaceXClient interface {
+//		AddOrUpdateHardcodedUser(rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*http.Response, error)
+//		AddOrUpdateHardcodedUserResponse(rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*AddOrUpdateHardcodedUserResponse, error)
+//	}
 func (e *evaluator) genOpsInterface() {
 	ops := e.queryDocument.Operations
 
 	funcs := make([]ds.Func, len(ops))
+	opStructs := make([][]ds.Struct, len(ops))
+	opUnions := make([][]ds.Union, len(ops))
 	for i, op := range ops {
+		e.position = op.Position
+		wrapperTypes, structs, unions := e.parseOperationTypes(op)
 		f := ds.Func{
 			Name:         op.Name,
 			Args:         e.parseFnArgs(&op.VariableDefinitions),
 			Type:         "(*http.Response, error)",
-			WrapperTypes: e.parseSelectionSet(op.SelectionSet),
+			WrapperTypes: wrapperTypes,
+			Subscription: op.Operation == ast.Subscription,
+			Mutation:     op.Operation == ast.Mutation,
+		}
+		f.Persisted = e.AdditionalInfo.PersistedQueries && !f.Subscription
+		if f.Subscription {
+			f.Type = fmt.Sprintf("(<-chan %s, error)", e.responseStructName(f))
 		}
-		funcs[i] = f
+		funcs[i] = e.modelOperation(f)
+		opStructs[i] = structs
+		opUnions[i] = unions
 	}
-	e.generator.WriteInterface(e.AdditionalInfo.ClientName, funcs...)
-	e.generator.WriteLineBreak(twoLinesBreak)
+	e.position = nil
 
-	// Generate interface implementation for each interface method.
+	// Each operation is exposed both as a raw method and as a method returning decoded response.
+	// Subscription is exposed as a single method returning channel of decoded events.
+	ifaceFuncs := make([]ds.Func, 0, 2*len(funcs))
 	for _, f := range funcs {
-		e.generator.WriteInterfaceImplementation(e.AdditionalInfo.ClientName, f)
-		e.generator.WriteLineBreak(twoLinesBreak)
+		if f.Subscription {
+			ifaceFuncs = append(ifaceFuncs, f)
+			continue
+		}
+		typedFunc := ds.Func{
+			Name: fmt.Sprintf("%sResponse", f.Name),
+			Args: f.Args,
+			Type: fmt.Sprintf("(*%s, error)", e.responseStructName(f)),
+		}
+		ifaceFuncs = append(ifaceFuncs, f, typedFunc)
+	}
+	e.report(e.generator.WriteInterface(e.AdditionalInfo.ClientName, ifaceFuncs...))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+
+	// Generate interface implementation for each interface method.
+	for i, f := range funcs {
+		e.position = ops[i].Position
+		if f.Subscription {
+			e.report(e.generator.WriteSubscriptionImplementation(e.AdditionalInfo.ClientName, f, e.responseStructName(f)))
+			e.report(e.generator.WriteLineBreak(twoLinesBreak))
+			continue
+		}
+		e.report(e.generator.WriteInterfaceImplementation(e.AdditionalInfo.ClientName, f))
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+
+		e.report(e.generator.WriteTypedInterfaceImplementation(e.AdditionalInfo.ClientName, f, e.responseStructName(f)))
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
 	}
 
 	// Generate wrapper struct for selection set operations.
-	for _, f := range funcs {
+	for i, f := range funcs {
+		e.position = ops[i].Position
 		e.genWrapperResponseStruct(f)
+		e.genOperationStructs(opStructs[i], opUnions[i])
 	}
+	e.position = nil
 
 	// Generate predefined error structs.
 	e.genErrorStructs()
@@ -391,9 +583,8 @@ func (e *evaluator) genOpsInterface() {
 // See https://graphql.org/learn/serving-over-http/#response
 func (e *evaluator) genWrapperResponseStruct(f ds.Func) {
 	dataStructName := fmt.Sprintf("%sData", strings.Title(f.Name))
-	responseStructName := fmt.Sprintf("%sResponse", strings.Title(f.Name))
 	structWrapper := ds.Struct{
-		Name: responseStructName,
+		Name: e.responseStructName(f),
 		Fields: []ds.TypeField{
 			{
 				Name:     "data",
@@ -407,22 +598,44 @@ func (e *evaluator) genWrapperResponseStruct(f ds.Func) {
 			},
 		},
 	}
-	e.generator.WritePublicStruct(structWrapper, e.AdditionalInfo.UsePointers)
-	e.generator.WriteLineBreak(twoLinesBreak)
+	if f.Subscription {
+		structWrapper.Fields = append(structWrapper.Fields, ds.TypeField{
+			Name:     "err",
+			Type:     "error",
+			JsonName: "-",
+		})
+	}
+	if structWrapper, ok := e.modelResponseStruct(structWrapper); ok {
+		e.report(e.generator.WritePublicStruct(structWrapper, e.AdditionalInfo.UsePointers))
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	}
 
 	// generate object referenced in 'data' JSON response.
 	// if object has selection set - those will be created as struct fields.
-	s := ds.Struct{
+	s, ok := e.modelResponseStruct(ds.Struct{
 		Name:   dataStructName,
 		Fields: f.WrapperTypes,
+	})
+	if !ok {
+		return
+	}
+	e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.genEmbeddedMarshallers(s)
+}
+
+// responseStructName returns name of the top level GraphQL response type generated for the operation.
+// Subscription events are named with Event suffix.
+func (e *evaluator) responseStructName(f ds.Func) string {
+	if f.Subscription {
+		return fmt.Sprintf("%sEvent", strings.Title(f.Name))
 	}
-	e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers)
-	e.generator.WriteLineBreak(twoLinesBreak)
+	return fmt.Sprintf("%sResponse", strings.Title(f.Name))
 }
 
 // genErrorStructs generates predefined GraphQL error structs.
 func (e *evaluator) genErrorStructs() {
-	e.generator.WriteGraphqlErrorStructs(e.AdditionalInfo.UsePointers)
+	e.report(e.generator.WriteGraphqlErrorStructs(e.AdditionalInfo.UsePointers, e.AdditionalInfo.NullablePointers))
 }
 
 // genClientStruct generates internal grafik GraphQL client defined in package client.
@@ -436,23 +649,5 @@ func (e *evaluator) genClientStruct() {
 			},
 		},
 	}
-	e.generator.WritePrivateStruct(s)
-}
-
-// removeComments removes comments from GraphQL queries.
-func (e *evaluator) removeComments(queryStr string) string {
-	const commentToken = "#"
-
-	var out bytes.Buffer
-	scanner := bufio.NewScanner(strings.NewReader(queryStr))
-
-	for scanner.Scan() {
-		splitLine := strings.Split(scanner.Text(), commentToken)
-		code := strings.TrimSpace(splitLine[0])
-		if code != "" {
-			out.WriteString(splitLine[0])
-			out.WriteRune('\n')
-		}
-	}
-	return strings.TrimRight(out.String(), "\n")
+	e.report(e.generator.WritePrivateStruct(s))
 }
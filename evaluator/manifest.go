@@ -0,0 +1,69 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/client"
+	"github.com/vektah/gqlparser/ast"
+	"io"
+)
+
+// ManifestFileName is the default name of the persisted operations manifest file.
+const ManifestFileName = "persisted-query-manifest.json"
+
+// manifestFormat and manifestVersion identify Apollo persisted query manifest format.
+// See https://www.apollographql.com/docs/graphos/operations/persisted-queries#manifest-format
+const (
+	manifestFormat  = "apollo-persisted-query-manifest"
+	manifestVersion = 1
+)
+
+// manifest represents persisted operations manifest.
+type manifest struct {
+	Format     string              `json:"format"`
+	Version    int                 `json:"version"`
+	Operations []manifestOperation `json:"operations"`
+}
+
+// manifestOperation represents single operation of persisted operations manifest.
+// ID is SHA-256 hash of the normalized operation body sent by grafik client.
+type manifestOperation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+// GenerateManifest generates persisted operations manifest mapping each operation to its normalized body and hash.
+// Subscriptions are skipped, as they are never sent as persisted queries.
+func (e *evaluator) GenerateManifest() (io.WriterTo, error) {
+	ops := e.queryDocument.Operations
+	queries := e.splitOperations()
+
+	m := manifest{
+		Format:     manifestFormat,
+		Version:    manifestVersion,
+		Operations: make([]manifestOperation, 0, len(ops)),
+	}
+	for i, op := range ops {
+		if op.Operation == ast.Subscription {
+			continue
+		}
+		m.Operations = append(m.Operations, manifestOperation{
+			ID:   client.PersistedQueryHash(queries[i]),
+			Name: op.Name,
+			Type: string(op.Operation),
+			Body: queries[i],
+		})
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, Diagnostics{{Err: fmt.Errorf("failed to marshal persisted operations manifest. Cause: %w", err)}}
+	}
+	b = append(b, '\n')
+	return bytes.NewBuffer(b), nil
+}
@@ -0,0 +1,108 @@
+package evaluator
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEvaluator_GenerateManifest(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/subscription/schema.graphql")
+	query := loadQuery(t, schema, "test/subscription/query.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	manifest, err := e.GenerateManifest()
+	assert.NoError(t, err)
+	src := &bytes.Buffer{}
+	_, err = manifest.WriteTo(src)
+	assert.NoError(t, err)
+
+	expOut := `{
+  "format": "apollo-persisted-query-manifest",
+  "version": 1,
+  "operations": [
+    {
+      "id": "4afc20918700fa6f3f05474e36e978fc49ae2c8e932430abf769137f0e4d343a",
+      "name": "GetRocket",
+      "type": "query",
+      "body": "query GetRocket($id:ID!){rocket(id:$id){id name}}"
+    }
+  ]
+}
+`
+	assert.Equal(t, expOut, src.String())
+}
+
+func TestEvaluator_GenerateManifest_SubscriptionOnly(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/subscription/schema.graphql")
+	query := loadQuery(t, schema, "test/subscription/subscription_only.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	manifest, err := e.GenerateManifest()
+	assert.NoError(t, err)
+	src := &bytes.Buffer{}
+	_, err = manifest.WriteTo(src)
+	assert.NoError(t, err)
+
+	expOut := `{
+  "format": "apollo-persisted-query-manifest",
+  "version": 1,
+  "operations": []
+}
+`
+	assert.Equal(t, expOut, src.String())
+}
+
+func TestEvaluator_GenerateManifest_OperationFragments(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/operation_fragments/schema.graphql")
+	query := loadQuery(t, schema, "test/operation_fragments/query.graphql")
+	info := AdditionalInfo{
+		PackageName: "grafik_client",
+		ClientName:  "RocketClient",
+	}
+	e := New(schema, query, info)
+
+	manifest, err := e.GenerateManifest()
+	assert.NoError(t, err)
+	src := &bytes.Buffer{}
+	_, err = manifest.WriteTo(src)
+	assert.NoError(t, err)
+
+	expOut := `{
+  "format": "apollo-persisted-query-manifest",
+  "version": 1,
+  "operations": [
+    {
+      "id": "816eb220559bca949aae677de3c85e5a3c65802c095948c8f24e3c3139d9a771",
+      "name": "GetRocketNames",
+      "type": "query",
+      "body": "query GetRocketNames{rockets{name}}"
+    },
+    {
+      "id": "3493dd287d4f0b44cb39f18e1bb7b4acd104ddc98527f6415e16d7c53d2c2805",
+      "name": "GetRocketCompanies",
+      "type": "query",
+      "body": "query GetRocketCompanies{rockets{...RocketCompany...RocketName}}fragment RocketCompany on Rocket{company...RocketCountry}fragment RocketCountry on Rocket{country}fragment RocketName on Rocket{name}"
+    },
+    {
+      "id": "865f073acf98300462a2bf2380870419830829fe64be06cbad323e0de80db1d3",
+      "name": "GetRocketCountries",
+      "type": "query",
+      "body": "query GetRocketCountries{rockets{...RocketCountry...on Rocket{...RocketName}}}fragment RocketCountry on Rocket{country}fragment RocketName on Rocket{name}"
+    }
+  ]
+}
+`
+	assert.Equal(t, expOut, src.String())
+}
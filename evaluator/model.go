@@ -0,0 +1,225 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/Bartosz-D3V/grafik/generator"
+	"github.com/Bartosz-D3V/grafik/plugin"
+)
+
+// modelBuilder collects intermediate model of grafik client in the first pass of the generation and provides values modified by plugins in the second one.
+// Values are indexed by their original names, so the second pass finds them even if plugins renamed them.
+// Names of operations and their response types are referenced by generated code outside the model, so they cannot be changed.
+type modelBuilder struct {
+	model         *plugin.Model
+	collecting    bool
+	types         map[string]*ds.Struct
+	enums         map[string]*ds.Enum
+	operations    map[string]*ds.Func
+	responseTypes []string
+}
+
+// collectModel renders grafik client into discarded output to collect its intermediate model and lets all plugins modify it.
+func (e *evaluator) collectModel() error {
+	g, err := generator.New()
+	if err != nil {
+		return Diagnostics{{Err: err}}
+	}
+	e.generator = g
+	e.model = &modelBuilder{
+		model: &plugin.Model{
+			PackageName: e.AdditionalInfo.PackageName,
+			ClientName:  e.AdditionalInfo.ClientName,
+		},
+		collecting: true,
+		types:      make(map[string]*ds.Struct),
+		enums:      make(map[string]*ds.Enum),
+		operations: make(map[string]*ds.Func),
+	}
+	e.render()
+	if err := e.err(); err != nil {
+		return err
+	}
+
+	operations := append([]*ds.Func{}, e.model.model.Operations...)
+	names := make([]string, len(operations))
+	for i, f := range operations {
+		names[i] = f.Name
+	}
+	for _, p := range e.AdditionalInfo.Plugins {
+		if err := p.Mutate(e.model.model); err != nil {
+			return Diagnostics{{Err: fmt.Errorf("plugin %s failed. Cause: %w", p.Name(), err)}}
+		}
+		if !sameOperations(operations, e.model.model.Operations) {
+			return Diagnostics{{Err: fmt.Errorf("plugin %s failed. Cause: operations cannot be appended or removed", p.Name())}}
+		}
+		if err := e.model.checkNames(names); err != nil {
+			return Diagnostics{{Err: fmt.Errorf("plugin %s failed. Cause: %w", p.Name(), err)}}
+		}
+	}
+	e.model.collecting = false
+	return nil
+}
+
+// modelStruct returns public struct modified by plugins and false if plugins removed it.
+// In the first pass the struct is added to the model.
+func (e *evaluator) modelStruct(s ds.Struct) (ds.Struct, bool) {
+	if e.model == nil {
+		return s, true
+	}
+	if e.model.collecting {
+		s.Fields = append([]ds.TypeField{}, s.Fields...)
+		e.model.types[s.Name] = &s
+		e.model.model.Types = append(e.model.model.Types, &s)
+		return s, true
+	}
+	m, ok := e.model.types[s.Name]
+	if !ok {
+		return s, true
+	}
+	return *m, containsStruct(e.model.model.Types, m)
+}
+
+// modelResponseStruct returns response type of the operation modified by plugins and false if plugins removed it.
+// In the first pass the struct is added to the model and its name is recorded, so plugins cannot rename it.
+func (e *evaluator) modelResponseStruct(s ds.Struct) (ds.Struct, bool) {
+	if e.model != nil && e.model.collecting {
+		e.model.responseTypes = append(e.model.responseTypes, s.Name)
+	}
+	return e.modelStruct(s)
+}
+
+// modelEnum returns enum modified by plugins and false if plugins removed it.
+// In the first pass the enum is added to the model.
+func (e *evaluator) modelEnum(en ds.Enum) (ds.Enum, bool) {
+	if e.model == nil {
+		return en, true
+	}
+	if e.model.collecting {
+		en.Fields = append([]string{}, en.Fields...)
+		e.model.enums[en.Name] = &en
+		e.model.model.Enums = append(e.model.model.Enums, &en)
+		return en, true
+	}
+	m, ok := e.model.enums[en.Name]
+	if !ok {
+		return en, true
+	}
+	return *m, containsEnum(e.model.model.Enums, m)
+}
+
+// modelOperation returns client method executing GraphQL operation modified by plugins.
+// In the first pass the method is added to the model.
+func (e *evaluator) modelOperation(f ds.Func) ds.Func {
+	if e.model == nil {
+		return f
+	}
+	if e.model.collecting {
+		f.Args = append([]ds.TypeArg{}, f.Args...)
+		f.WrapperTypes = append([]ds.TypeField{}, f.WrapperTypes...)
+		e.model.operations[f.Name] = &f
+		e.model.model.Operations = append(e.model.model.Operations, &f)
+		return f
+	}
+	if m, ok := e.model.operations[f.Name]; ok {
+		return *m
+	}
+	return f
+}
+
+// genModelAdditions generates types and enums appended to the model by plugins together with their declarations.
+func (e *evaluator) genModelAdditions() {
+	if e.model == nil || e.model.collecting {
+		return
+	}
+	for _, en := range e.model.model.Enums {
+		if e.model.isCollectedEnum(en) {
+			continue
+		}
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+		e.report(e.generator.WriteEnum(*en))
+	}
+	for _, s := range e.model.model.Types {
+		if e.model.isCollectedStruct(s) {
+			continue
+		}
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+		e.report(e.generator.WritePublicStruct(*s, e.AdditionalInfo.UsePointers))
+	}
+	for _, decl := range e.model.model.Declarations {
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+		e.report(e.generator.WriteDeclaration(decl))
+	}
+}
+
+// isCollectedStruct checks if the struct was collected in the first pass - i.e. it was not appended by plugins.
+func (m *modelBuilder) isCollectedStruct(s *ds.Struct) bool {
+	for _, collected := range m.types {
+		if collected == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isCollectedEnum checks if the enum was collected in the first pass - i.e. it was not appended by plugins.
+func (m *modelBuilder) isCollectedEnum(en *ds.Enum) bool {
+	for _, collected := range m.enums {
+		if collected == en {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNames checks if plugins kept names of operations and kept their response types with the same names.
+// Operation names are listed in the original order of operations.
+func (m *modelBuilder) checkNames(operationNames []string) error {
+	for i, f := range m.model.Operations {
+		if f.Name != operationNames[i] {
+			return fmt.Errorf("operation %s cannot be renamed", operationNames[i])
+		}
+	}
+	for _, name := range m.responseTypes {
+		s := m.types[name]
+		if s.Name != name || !containsStruct(m.model.Types, s) {
+			return fmt.Errorf("response type %s cannot be renamed or removed", name)
+		}
+	}
+	return nil
+}
+
+// sameOperations checks if plugins kept all operations of the model in the same order.
+func sameOperations(exp []*ds.Func, act []*ds.Func) bool {
+	if len(exp) != len(act) {
+		return false
+	}
+	for i := range exp {
+		if exp[i] != act[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsStruct checks if the model still contains the struct.
+func containsStruct(structs []*ds.Struct, s *ds.Struct) bool {
+	for _, m := range structs {
+		if m == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsEnum checks if the model still contains the enum.
+func containsEnum(enums []*ds.Enum, en *ds.Enum) bool {
+	for _, m := range enums {
+		if m == en {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/common"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/vektah/gqlparser/ast"
+	"strings"
+)
+
+// selectedField groups all occurrences of the same response key (alias) within a single selection set.
+// conditional is set if every occurrence may be omitted from the response because of @include or @skip directive.
+type selectedField struct {
+	field       *ast.Field
+	selections  ast.SelectionSet
+	conditional bool
+}
+
+// parseOperationTypes returns fields of the operation 'data' struct and all nested structs and unions used by them.
+// Unless AdditionalInfo.PerOperationTypes is set, fields reference types shared between all operations and no nested structs are returned.
+func (e *evaluator) parseOperationTypes(op *ast.OperationDefinition) ([]ds.TypeField, []ds.Struct, []ds.Union) {
+	if !e.AdditionalInfo.PerOperationTypes {
+		return e.parseSelectionSet(op.SelectionSet), nil, nil
+	}
+	fields, structs, unions := e.parseOperationSelectionSet(strings.Title(op.Name), op.SelectionSet)
+	e.checkOperationTypeNames(op, structs, unions)
+	return fields, structs, unions
+}
+
+// checkOperationTypeNames reports nested types of the operation with the same name as another nested type or response type of the operation.
+// Names of nested types are built from aliases, so i.e. aliases 'rocket_info' and 'rocketInfo' (or alias 'data' of the root field) produce the same name.
+func (e *evaluator) checkOperationTypeNames(op *ast.OperationDefinition, structs []ds.Struct, unions []ds.Union) {
+	f := ds.Func{Name: op.Name, Subscription: op.Operation == ast.Subscription}
+	names := map[string]bool{
+		e.responseStructName(f):                       true,
+		fmt.Sprintf("%sData", strings.Title(op.Name)): true,
+	}
+	typeNames := make([]string, 0, len(structs)+2*len(unions))
+	for _, s := range structs {
+		typeNames = append(typeNames, s.Name)
+	}
+	for _, u := range unions {
+		typeNames = append(typeNames, u.Name, u.InterfaceName)
+	}
+	for _, name := range typeNames {
+		if names[name] {
+			e.report(fmt.Errorf("type %s is generated more than once for operation %s - use different aliases of its fields", name, op.Name))
+		}
+		names[name] = true
+	}
+}
+
+// parseOperationSelectionSet converts selection set into struct fields containing exactly the selected fields.
+// Each field with its own selection set gets a dedicated struct named after the path from the operation root.
+// For example query 'getRockets { rockets { name } }' generates field Rockets of type []GetRocketsRockets
+// and struct GetRocketsRockets with a single field Name.
+// If AdditionalInfo.FragmentStructs is set, fragments spread in the selection set are embedded instead.
+func (e *evaluator) parseOperationSelectionSet(typeName string, set ast.SelectionSet) ([]ds.TypeField, []ds.Struct, []ds.Union) {
+	fields := collectFields(set, make([]*selectedField, 0), !e.AdditionalInfo.FragmentStructs)
+	return e.parseOperationFields(typeName, fields, e.spreadFragments([]ast.SelectionSet{set}, matchAnyType))
+}
+
+// parseOperationFields converts collected fields into struct fields and nested structs named after the path from the operation root.
+// Structs of fragments are embedded before the fields.
+// If AdditionalInfo.DiscriminatedUnions is set, fields of GraphQL interface or union types are generated as unions instead of nested structs.
+// Fields that may be omitted from the response because of @include or @skip directive are generated as pointers.
+func (e *evaluator) parseOperationFields(typeName string, fields []*selectedField, fragments []*ast.FragmentDefinition) ([]ds.TypeField, []ds.Struct, []ds.Union) {
+	typeFields := embeddedFields(fragments)
+	structs := make([]ds.Struct, 0)
+	unions := make([]ds.Union, 0)
+	for _, f := range fields {
+		if goName, ok := e.SpecialGraphqlTypesMapping[f.field.Name]; ok {
+			typeFields = append(typeFields, optionalField(ds.TypeField{
+				Name:     goName,
+				Type:     e.mapSpecialType(f.field.Name),
+				JsonName: f.field.Alias,
+				NonNull:  true,
+			}, f.conditional))
+			continue
+		}
+
+		fieldType := e.convFieldType(f.field.Definition.Type)
+		if len(f.selections) > 0 {
+			structName := fmt.Sprintf("%s%s", typeName, common.SnakeCaseToCamelCase(strings.Title(f.field.Alias)))
+			if e.isDiscriminated(f.field.Definition.Type) {
+				u, nestedStructs, nestedUnions := e.parseOperationUnion(structName, f.field.Definition.Type, f.selections)
+				structs = append(structs, nestedStructs...)
+				unions = append(unions, nestedUnions...)
+				unions = append(unions, u)
+			} else {
+				nestedFields, nestedStructs, nestedUnions := e.parseOperationSelectionSet(structName, f.selections)
+				structs = append(structs, ds.Struct{
+					Name:   structName,
+					Fields: nestedFields,
+				})
+				structs = append(structs, nestedStructs...)
+				unions = append(unions, nestedUnions...)
+			}
+			fieldType = e.convNamedType(f.field.Definition.Type, structName)
+		}
+
+		typeFields = append(typeFields, optionalField(ds.TypeField{
+			Name:     f.field.Alias,
+			Type:     fieldType,
+			JsonName: f.field.Alias,
+			NonNull:  f.field.Definition.Type.NonNull,
+		}, f.conditional))
+	}
+	return typeFields, structs, unions
+}
+
+// convNamedType returns Go type of astType with the leaf type replaced by name.
+// I.e. [[Character]] -> [][]name.
+// If AdditionalInfo.NullablePointers is set nullable types (including list elements) are returned as pointers - i.e. [[Character]!] -> [][]*name.
+func (e *evaluator) convNamedType(astType *ast.Type, name string) string {
+	if common.IsList(astType) {
+		return fmt.Sprintf("[]%s", e.convNamedType(astType.Elem, name))
+	}
+	if e.AdditionalInfo.NullablePointers && !astType.NonNull {
+		return fmt.Sprintf("*%s", name)
+	}
+	return name
+}
+
+// genOperationStructs writes structs and unions generated for a single operation.
+// Members of unions are referenced by the names of structs modified by plugins - members removed by plugins are skipped.
+func (e *evaluator) genOperationStructs(structs []ds.Struct, unions []ds.Union) {
+	names := make(map[string]string, len(structs))
+	for _, s := range structs {
+		name := s.Name
+		s, ok := e.modelStruct(s)
+		if !ok {
+			continue
+		}
+		names[name] = s.Name
+		e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+		e.genEmbeddedMarshallers(s)
+	}
+	for _, u := range unions {
+		members := make([]ds.UnionMember, 0, len(u.Members))
+		for _, m := range u.Members {
+			if name, ok := names[m.StructName]; ok {
+				members = append(members, ds.UnionMember{TypeName: m.TypeName, StructName: name})
+			}
+		}
+		u.Members = members
+		e.report(e.generator.WriteUnion(u))
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	}
+}
+
+// collectFields flattens fields, inline fragments and fragment spreads into an ordered list of fields.
+// Fields selected multiple times under the same alias are merged into one with all sub-selections.
+// Fragment spreads are skipped unless flattenSpreads is set - i.e. if they are generated as embedded structs.
+func collectFields(set ast.SelectionSet, fields []*selectedField, flattenSpreads bool) []*selectedField {
+	return collectMatchingFields(set, fields, matchAnyType, flattenSpreads)
+}
+
+// collectMatchingFields flattens fields like collectFields, but skips fragments whose type condition does not match.
+func collectMatchingFields(set ast.SelectionSet, fields []*selectedField, matches func(typeCondition string) bool, flattenSpreads bool) []*selectedField {
+	return collectSelections(set, fields, matches, flattenSpreads, false)
+}
+
+// collectSelections flattens fields like collectMatchingFields. Fields are collected as conditional if conditional is set
+// or if they, or fragments selecting them, have @include or @skip directive.
+func collectSelections(set ast.SelectionSet, fields []*selectedField, matches func(typeCondition string) bool, flattenSpreads bool, conditional bool) []*selectedField {
+	for _, selection := range set {
+		switch selectionType := selection.(type) {
+		case *ast.Field:
+			fieldConditional := conditional || isConditional(selectionType.Directives)
+			if f := findSelectedField(fields, selectionType.Alias); f != nil {
+				f.selections = append(f.selections, selectionType.SelectionSet...)
+				f.conditional = f.conditional && fieldConditional
+				continue
+			}
+			fields = append(fields, &selectedField{
+				field:       selectionType,
+				selections:  append(ast.SelectionSet{}, selectionType.SelectionSet...),
+				conditional: fieldConditional,
+			})
+		case *ast.InlineFragment:
+			if matches(selectionType.TypeCondition) {
+				fields = collectSelections(selectionType.SelectionSet, fields, matches, flattenSpreads, conditional || isConditional(selectionType.Directives))
+			}
+		case *ast.FragmentSpread:
+			if flattenSpreads && matches(selectionType.Definition.TypeCondition) {
+				fields = collectSelections(selectionType.Definition.SelectionSet, fields, matches, flattenSpreads, conditional || isConditional(selectionType.Directives))
+			}
+		}
+	}
+	return fields
+}
+
+// findSelectedField returns already collected field with given alias or nil if it does not exist.
+func findSelectedField(fields []*selectedField, alias string) *selectedField {
+	for _, f := range fields {
+		if f.field.Alias == alias {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"fmt"
+	"github.com/vektah/gqlparser/ast"
+	"strings"
+)
+
+// queryPrinter prints GraphQL query document from its AST without comments and ignored characters.
+// Tokens are separated by a space only if both of them are names, numbers or strings.
+type queryPrinter struct {
+	strings.Builder
+	// word is set if the last printed token is not a punctuator.
+	word bool
+}
+
+// printQueryDocument prints the query document in canonical, minified form - i.e.
+//
+//	query getRocket($id:ID!){rocket(id:$id){id ...RocketName}}fragment RocketName on Rocket{name}
+func printQueryDocument(doc *ast.QueryDocument) string {
+	p := &queryPrinter{}
+	for _, op := range doc.Operations {
+		p.operation(op)
+	}
+	for _, f := range doc.Fragments {
+		p.fragment(f)
+	}
+	return p.String()
+}
+
+// punctuator prints GraphQL punctuator - i.e. {, ( or ...
+func (p *queryPrinter) punctuator(s string) {
+	p.WriteString(s)
+	p.word = false
+}
+
+// token prints GraphQL name, number or string separated from the previous one.
+func (p *queryPrinter) token(s string) {
+	if p.word {
+		p.WriteByte(' ')
+	}
+	p.WriteString(s)
+	p.word = true
+}
+
+func (p *queryPrinter) operation(op *ast.OperationDefinition) {
+	p.token(string(op.Operation))
+	if op.Name != "" {
+		p.token(op.Name)
+	}
+	if len(op.VariableDefinitions) > 0 {
+		p.punctuator("(")
+		for _, v := range op.VariableDefinitions {
+			p.punctuator("$")
+			p.token(v.Variable)
+			p.punctuator(":")
+			p.typeReference(v.Type)
+			if v.DefaultValue != nil {
+				p.punctuator("=")
+				p.value(v.DefaultValue)
+			}
+		}
+		p.punctuator(")")
+	}
+	p.directives(op.Directives)
+	p.selectionSet(op.SelectionSet)
+}
+
+func (p *queryPrinter) fragment(f *ast.FragmentDefinition) {
+	p.token("fragment")
+	p.token(f.Name)
+	p.token("on")
+	p.token(f.TypeCondition)
+	p.directives(f.Directives)
+	p.selectionSet(f.SelectionSet)
+}
+
+func (p *queryPrinter) selectionSet(set ast.SelectionSet) {
+	p.punctuator("{")
+	for _, sel := range set {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if s.Alias != "" && s.Alias != s.Name {
+				p.token(s.Alias)
+				p.punctuator(":")
+			}
+			p.token(s.Name)
+			p.arguments(s.Arguments)
+			p.directives(s.Directives)
+			if len(s.SelectionSet) > 0 {
+				p.selectionSet(s.SelectionSet)
+			}
+		case *ast.FragmentSpread:
+			p.punctuator("...")
+			p.token(s.Name)
+			p.directives(s.Directives)
+		case *ast.InlineFragment:
+			p.punctuator("...")
+			if s.TypeCondition != "" {
+				p.token("on")
+				p.token(s.TypeCondition)
+			}
+			p.directives(s.Directives)
+			p.selectionSet(s.SelectionSet)
+		}
+	}
+	p.punctuator("}")
+}
+
+func (p *queryPrinter) arguments(args ast.ArgumentList) {
+	if len(args) == 0 {
+		return
+	}
+	p.punctuator("(")
+	for _, arg := range args {
+		p.token(arg.Name)
+		p.punctuator(":")
+		p.value(arg.Value)
+	}
+	p.punctuator(")")
+}
+
+func (p *queryPrinter) directives(directives ast.DirectiveList) {
+	for _, d := range directives {
+		p.punctuator("@")
+		p.token(d.Name)
+		p.arguments(d.Arguments)
+	}
+}
+
+func (p *queryPrinter) typeReference(t *ast.Type) {
+	if t.NamedType != "" {
+		p.token(t.NamedType)
+	} else {
+		p.punctuator("[")
+		p.typeReference(t.Elem)
+		p.punctuator("]")
+	}
+	if t.NonNull {
+		p.punctuator("!")
+	}
+}
+
+func (p *queryPrinter) value(v *ast.Value) {
+	switch v.Kind {
+	case ast.Variable:
+		p.punctuator("$")
+		p.token(v.Raw)
+	case ast.StringValue, ast.BlockValue:
+		p.token(quoteString(v.Raw))
+	case ast.ListValue:
+		p.punctuator("[")
+		for _, c := range v.Children {
+			p.value(c.Value)
+		}
+		p.punctuator("]")
+	case ast.ObjectValue:
+		p.punctuator("{")
+		for _, c := range v.Children {
+			p.token(c.Name)
+			p.punctuator(":")
+			p.value(c.Value)
+		}
+		p.punctuator("}")
+	default:
+		p.token(v.Raw)
+	}
+}
+
+// quoteString returns GraphQL string literal of the value. Block strings are printed as strings with the same value.
+// Backtick is escaped as well, so the query can be written as Go raw string literal.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < ' ' || r == '`' {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package evaluator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser"
+	"github.com/vektah/gqlparser/ast"
+	"testing"
+)
+
+func TestPrintQueryDocument(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t, "test/query_printing/schema.graphql")
+	query := loadQuery(t, schema, "test/query_printing/query.graphql")
+
+	out := printQueryDocument(query)
+	exp := `query searchRockets($search:String="falcon # 9"$limit:Int=10$withName:Boolean!)` +
+		`{rockets(search:$search limit:$limit filter:{name:"\u0060Falcon\u0060" tags:["a" "b\"c"]}ids:["1" "2"])` +
+		`{id title:name@include(if:$withName)description(format:"Multi-line\n  block string # not a comment")` +
+		`...on Rocket@skip(if:false){...RocketName}}}` +
+		`fragment RocketName on Rocket{name}`
+	assert.Equal(t, exp, out)
+
+	// Printed query is parsed to the same document.
+	printed, err := gqlparser.LoadQuery(schema, out)
+	assert.Nil(t, err)
+	assert.Equal(t, out, printQueryDocument(printed))
+	assert.Equal(t, "`Falcon`", printed.Operations[0].SelectionSet[0].(*ast.Field).Arguments.ForName("filter").Value.Children[0].Value.Raw)
+}
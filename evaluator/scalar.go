@@ -0,0 +1,113 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/vektah/gqlparser/ast"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoTypeRef represents Go type that custom GraphQL scalar is bound to.
+// ImportPath is empty for predeclared types - i.e. "string".
+// Type is the type qualified by package name - i.e. "time.Time".
+type GoTypeRef struct {
+	ImportPath string
+	Type       string
+}
+
+// ParseGoTypeRef parses Go type reference in the form of [import/path.]TypeName.
+// I.e. "time.Time", "encoding/json.RawMessage", "github.com/google/uuid.UUID" or "string".
+func ParseGoTypeRef(ref string) (GoTypeRef, error) {
+	sep := strings.LastIndex(ref, ".")
+	if sep == -1 {
+		if !token.IsIdentifier(ref) {
+			return GoTypeRef{}, fmt.Errorf("invalid Go type %q", ref)
+		}
+		return GoTypeRef{Type: ref}, nil
+	}
+
+	importPath, typeName := ref[:sep], ref[sep+1:]
+	if importPath == "" || !token.IsIdentifier(typeName) {
+		return GoTypeRef{}, fmt.Errorf("invalid Go type %q - expected [import/path.]TypeName", ref)
+	}
+
+	return GoTypeRef{
+		ImportPath: importPath,
+		Type:       fmt.Sprintf("%s.%s", packageName(importPath), typeName),
+	}, nil
+}
+
+// packageName guesses name of the package based on its import path, skipping major version suffixes.
+// I.e. "github.com/org/lib/v2" -> "lib", "gopkg.in/yaml.v3" -> "yaml", "github.com/org/go-lib" -> "lib".
+func packageName(importPath string) string {
+	pkgName := path.Base(importPath)
+	if isMajorVersion(pkgName) {
+		pkgName = path.Base(path.Dir(importPath))
+	} else if sep := strings.LastIndex(pkgName, "."); sep != -1 && isMajorVersion(pkgName[sep+1:]) {
+		pkgName = pkgName[:sep]
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(pkgName, "go-"), "-", "")
+}
+
+// isMajorVersion returns true if s is a major version suffix of the import path - i.e. "v2".
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+	return err == nil
+}
+
+// scalarBinding returns Go type bound to the custom GraphQL scalar and true, or false if scalar is not bound.
+// Invalid bindings are reported by validateScalarBindings and treated as not bound.
+func (e *evaluator) scalarBinding(name string) (GoTypeRef, bool) {
+	ref, ok := e.AdditionalInfo.ScalarBindings[name]
+	if !ok {
+		return GoTypeRef{}, false
+	}
+	goType, err := ParseGoTypeRef(ref)
+	if err != nil {
+		return GoTypeRef{}, false
+	}
+	return goType, true
+}
+
+// validateScalarBindings reports invalid bindings of custom GraphQL scalars used in the query document at the position of the scalar definition.
+func (e *evaluator) validateScalarBindings() {
+	names := make([]string, 0, len(e.AdditionalInfo.ScalarBindings))
+	for name := range e.AdditionalInfo.ScalarBindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, used := e.customTypes[name]; !used {
+			continue
+		}
+		if _, err := ParseGoTypeRef(e.AdditionalInfo.ScalarBindings[name]); err != nil {
+			var pos *ast.Position
+			if def, ok := e.schema.Types[name]; ok {
+				pos = def.Position
+			}
+			e.reportAt(pos, fmt.Errorf("failed to bind GraphQL scalar %s. Cause: %w", name, err))
+		}
+	}
+}
+
+// scalarImports returns imports of Go types bound to custom GraphQL scalars used in the query document.
+func (e *evaluator) scalarImports() []ds.Import {
+	imports := make([]ds.Import, 0)
+	for name := range e.customTypes {
+		goType, ok := e.scalarBinding(name)
+		if ok && goType.ImportPath != "" {
+			imports = append(imports, ds.Import{Path: goType.ImportPath})
+		}
+	}
+	return imports
+}
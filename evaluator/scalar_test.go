@@ -0,0 +1,44 @@
+package evaluator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseGoTypeRef(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ref string
+		exp GoTypeRef
+	}{
+		{"string", GoTypeRef{Type: "string"}},
+		{"time.Time", GoTypeRef{ImportPath: "time", Type: "time.Time"}},
+		{"encoding/json.RawMessage", GoTypeRef{ImportPath: "encoding/json", Type: "json.RawMessage"}},
+		{"github.com/google/uuid.UUID", GoTypeRef{ImportPath: "github.com/google/uuid", Type: "uuid.UUID"}},
+		{"github.com/shopspring/decimal/v2.Decimal", GoTypeRef{ImportPath: "github.com/shopspring/decimal/v2", Type: "decimal.Decimal"}},
+		{"gopkg.in/yaml.v3.Node", GoTypeRef{ImportPath: "gopkg.in/yaml.v3", Type: "yaml.Node"}},
+		{"github.com/gofrs/go-uuid.UUID", GoTypeRef{ImportPath: "github.com/gofrs/go-uuid", Type: "uuid.UUID"}},
+	}
+	for _, test := range tests {
+		goType, err := ParseGoTypeRef(test.ref)
+		assert.NoError(t, err)
+		assert.Equal(t, test.exp, goType)
+	}
+}
+
+func TestParseGoTypeRef_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ref    string
+		expErr string
+	}{
+		{"", "invalid Go type \"\""},
+		{"time.", "invalid Go type \"time.\" - expected [import/path.]TypeName"},
+		{".Time", "invalid Go type \".Time\" - expected [import/path.]TypeName"},
+		{"map[string]int", "invalid Go type \"map[string]int\""},
+	}
+	for _, test := range tests {
+		_, err := ParseGoTypeRef(test.ref)
+		assert.EqualError(t, err, test.expErr)
+	}
+}
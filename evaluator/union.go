@@ -0,0 +1,209 @@
+// Package evaluator contains the logic responsible for evaluating schema & query GraphQL Abstract Syntax Tree [AST].
+// It orchestrates the generation of the code by using visitor & generator packages.
+package evaluator
+
+import (
+	"github.com/Bartosz-D3V/grafik/common"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/vektah/gqlparser/ast"
+)
+
+const (
+	typenameField          = "__typename"
+	unionValueStructSuffix = "Value"
+)
+
+// createUnion creates Go interface of GraphQL interface or union with one concrete struct per possible type and writes them to IO.
+// Each struct contains fields selected for its possible type - fields of the interface and fields of fragments matching the type.
+// The interface is wrapped by struct named with graphQLTypeSuffix that decodes the value based on __typename.
+func (e *evaluator) createUnion(cType *ast.Definition, graphQLTypeSuffix string) {
+	sets := make([]ast.SelectionSet, 0)
+	for _, f := range e.abstractFields() {
+		if common.LeafType(f.Definition.Type).NamedType == cType.Name {
+			sets = append(sets, f.SelectionSet)
+		}
+	}
+	flattenSpreads := !e.AdditionalInfo.FragmentStructs
+
+	u := ds.Union{
+		Name:          cType.Name + graphQLTypeSuffix,
+		InterfaceName: cType.Name,
+	}
+	for _, possible := range e.schema.GetPossibleTypes(cType) {
+		matches := e.possibleTypeMatcher(possible)
+		fields := make([]*selectedField, 0)
+		for _, set := range sets {
+			fields = collectMatchingFields(set, fields, matches, flattenSpreads)
+		}
+		fragments := e.spreadFragments(sets, matches)
+
+		s, ok := e.modelStruct(ds.Struct{
+			Name:   cType.Name + possible.Name,
+			Fields: append(embeddedFields(fragments), e.parseFieldArgs(&possible.Fields, fieldNames(fields), conditionalFields(sets, matches, flattenSpreads))...),
+		})
+		if !ok {
+			continue
+		}
+		e.report(e.generator.WriteLineBreak(twoLinesBreak))
+		e.report(e.generator.WritePublicStruct(s, e.AdditionalInfo.UsePointers))
+		e.genEmbeddedMarshallers(s)
+		u.Members = append(u.Members, ds.UnionMember{TypeName: possible.Name, StructName: s.Name})
+	}
+	e.report(e.generator.WriteLineBreak(twoLinesBreak))
+	e.report(e.generator.WriteUnion(u))
+}
+
+// parseOperationUnion converts selection set of GraphQL interface or union field into Go interface named with Value suffix and one concrete struct per possible type.
+// I.e. field 'characters' of query 'getCharacters' generates struct GetCharactersCharacters wrapping interface GetCharactersCharactersValue
+// implemented by GetCharactersCharactersHuman and GetCharactersCharactersDroid.
+func (e *evaluator) parseOperationUnion(structName string, astType *ast.Type, set ast.SelectionSet) (ds.Union, []ds.Struct, []ds.Union) {
+	u := ds.Union{
+		Name:          structName,
+		InterfaceName: structName + unionValueStructSuffix,
+	}
+	structs := make([]ds.Struct, 0)
+	unions := make([]ds.Union, 0)
+	for _, possible := range e.schema.GetPossibleTypes(e.schema.Types[common.LeafType(astType).NamedType]) {
+		memberName := structName + possible.Name
+		matches := e.possibleTypeMatcher(possible)
+		fields := collectMatchingFields(set, make([]*selectedField, 0), matches, !e.AdditionalInfo.FragmentStructs)
+		fragments := e.spreadFragments([]ast.SelectionSet{set}, matches)
+		typeFields, nestedStructs, nestedUnions := e.parseOperationFields(memberName, fields, fragments)
+		structs = append(structs, ds.Struct{
+			Name:   memberName,
+			Fields: typeFields,
+		})
+		structs = append(structs, nestedStructs...)
+		unions = append(unions, nestedUnions...)
+		u.Members = append(u.Members, ds.UnionMember{TypeName: possible.Name, StructName: memberName})
+	}
+	return u, structs, unions
+}
+
+// possibleTypeMatcher returns function checking if fragment with given type condition is selected for the possible type of GraphQL interface or union.
+func (e *evaluator) possibleTypeMatcher(possible *ast.Definition) func(typeCondition string) bool {
+	return func(typeCondition string) bool {
+		if typeCondition == "" || typeCondition == possible.Name {
+			return true
+		}
+		def, ok := e.schema.Types[typeCondition]
+		if !ok || !isAbstractKind(def.Kind) {
+			return false
+		}
+		for _, t := range e.schema.GetPossibleTypes(def) {
+			if t.Name == possible.Name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isAbstractKind returns true if GraphQL type of given kind is resolved to one of its possible types - i.e. it is interface or union.
+func isAbstractKind(kind ast.DefinitionKind) bool {
+	return kind == ast.Interface || kind == ast.Union
+}
+
+// isDiscriminated returns true if GraphQL type is generated as Go interface with concrete struct per possible type.
+func (e *evaluator) isDiscriminated(astType *ast.Type) bool {
+	def, ok := e.schema.Types[common.LeafType(astType).NamedType]
+	return e.AdditionalInfo.DiscriminatedUnions && ok && isAbstractKind(def.Kind)
+}
+
+// abstractFields returns all fields of GraphQL interface or union types selected by operations if AdditionalInfo.DiscriminatedUnions is set.
+func (e *evaluator) abstractFields() []*ast.Field {
+	return e.selectedFields(func(f *ast.Field) bool {
+		return len(f.SelectionSet) > 0 && e.isDiscriminated(f.Definition.Type)
+	})
+}
+
+// selectedFields returns all fields selected by operations matching the filter, including fields of used fragments.
+// Each fragment is visited once, so each field is returned only once.
+func (e *evaluator) selectedFields(filter func(f *ast.Field) bool) []*ast.Field {
+	fields := make([]*ast.Field, 0)
+	visited := make(map[string]bool)
+	var walk func(set ast.SelectionSet)
+	walk = func(set ast.SelectionSet) {
+		for _, sel := range set {
+			switch s := sel.(type) {
+			case *ast.Field:
+				if s.Definition != nil && filter(s) {
+					fields = append(fields, s)
+				}
+				walk(s.SelectionSet)
+			case *ast.InlineFragment:
+				walk(s.SelectionSet)
+			case *ast.FragmentSpread:
+				if s.Definition == nil || visited[s.Name] {
+					continue
+				}
+				visited[s.Name] = true
+				walk(s.Definition.SelectionSet)
+			}
+		}
+	}
+	for _, op := range e.queryDocument.Operations {
+		walk(op.SelectionSet)
+	}
+	return fields
+}
+
+// unionImports returns imports required by generated decoding of GraphQL interfaces and unions.
+func (e *evaluator) unionImports() []ds.Import {
+	if len(e.abstractFields()) == 0 {
+		return nil
+	}
+	return []ds.Import{{Path: "encoding/json"}, {Path: "fmt"}}
+}
+
+// printedOperation returns copy of the operation printed as its query with __typename selections inserted by typenameSelectionSet.
+func (e *evaluator) printedOperation(op *ast.OperationDefinition) *ast.OperationDefinition {
+	printed := *op
+	printed.SelectionSet = e.typenameSelectionSet(op.SelectionSet)
+	return &printed
+}
+
+// printedFragment returns copy of the fragment printed in the query with __typename selections inserted by typenameSelectionSet.
+func (e *evaluator) printedFragment(f *ast.FragmentDefinition) *ast.FragmentDefinition {
+	printed := *f
+	printed.SelectionSet = e.typenameSelectionSet(f.SelectionSet)
+	return &printed
+}
+
+// typenameSelectionSet returns copy of the selection set with __typename inserted into each selection set of GraphQL interface or union that does not select it,
+// so the response can be decoded into the matching struct. The selection set is not modified.
+func (e *evaluator) typenameSelectionSet(set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+	printed := make(ast.SelectionSet, len(set))
+	for i, sel := range set {
+		switch s := sel.(type) {
+		case *ast.Field:
+			field := *s
+			field.SelectionSet = e.typenameSelectionSet(s.SelectionSet)
+			if len(s.SelectionSet) > 0 && s.Definition != nil && e.isDiscriminated(s.Definition.Type) && !selectsTypename(s.SelectionSet) {
+				typename := &ast.Field{Alias: typenameField, Name: typenameField}
+				field.SelectionSet = append(ast.SelectionSet{typename}, field.SelectionSet...)
+			}
+			printed[i] = &field
+		case *ast.InlineFragment:
+			fragment := *s
+			fragment.SelectionSet = e.typenameSelectionSet(s.SelectionSet)
+			printed[i] = &fragment
+		default:
+			printed[i] = sel
+		}
+	}
+	return printed
+}
+
+// selectsTypename returns true if __typename is selected directly in the selection set without an alias.
+func selectsTypename(set ast.SelectionSet) bool {
+	for _, sel := range set {
+		if f, ok := sel.(*ast.Field); ok && f.Alias == typenameField {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -38,17 +39,11 @@ const (
 	Twitter   UsersUpdateColumn = "twitter"
 )
 
-const addOrUpdateHardcodedUser = `mutation addOrUpdateHardcodedUser($rocketName: String, $usersOnConflict: users_on_conflict) {
-        insert_users(objects: {id: "5b8bcf27-9561-4123-87ff-75088c9da9c7", rocket: $rocketName}, on_conflict: $usersOnConflict) {
-            affected_rows
-            returning {
-                id
-            }
-        }
-}`
+const addOrUpdateHardcodedUser = `mutation addOrUpdateHardcodedUser($rocketName:String$usersOnConflict:users_on_conflict){insert_users(objects:{id:"5b8bcf27-9561-4123-87ff-75088c9da9c7" rocket:$rocketName}on_conflict:$usersOnConflict){affected_rows returning{id}}}`
 
 type SpaceXClient interface {
 	AddOrUpdateHardcodedUser(ctx context.Context, rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*http.Response, error)
+	AddOrUpdateHardcodedUserResponse(ctx context.Context, rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*AddOrUpdateHardcodedUserResponse, error)
 }
 
 func (c *spaceXClient) AddOrUpdateHardcodedUser(ctx context.Context, rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*http.Response, error) {
@@ -59,6 +54,22 @@ func (c *spaceXClient) AddOrUpdateHardcodedUser(ctx context.Context, rocketName
 	return c.ctrl.Execute(ctx, addOrUpdateHardcodedUser, params, header)
 }
 
+func (c *spaceXClient) AddOrUpdateHardcodedUserResponse(ctx context.Context, rocketName string, usersOnConflict UsersOnConflict, header http.Header) (*AddOrUpdateHardcodedUserResponse, error) {
+	res, err := c.AddOrUpdateHardcodedUser(ctx, rocketName, usersOnConflict, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out AddOrUpdateHardcodedUserResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type AddOrUpdateHardcodedUserResponse struct {
 	Data   AddOrUpdateHardcodedUserData `json:"data"`
 	Errors []GraphQLError               `json:"errors"`
@@ -87,8 +98,8 @@ type spaceXClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) SpaceXClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) SpaceXClient {
 	return &spaceXClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -20,20 +21,11 @@ type Language struct {
 	Name string `json:"name"`
 }
 
-const getPolandInfo = `query getPolandInfo {
-    country(code: "PL") {
-        name
-        native
-        emoji
-        currency
-        languages {
-            name
-        }
-    }
-}`
+const getPolandInfo = `query getPolandInfo{country(code:"PL"){name native emoji currency languages{name}}}`
 
 type CountriesClient interface {
 	GetPolandInfo(ctx context.Context, header http.Header) (*http.Response, error)
+	GetPolandInfoResponse(ctx context.Context, header http.Header) (*GetPolandInfoResponse, error)
 }
 
 func (c *countriesClient) GetPolandInfo(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -42,6 +34,22 @@ func (c *countriesClient) GetPolandInfo(ctx context.Context, header http.Header)
 	return c.ctrl.Execute(ctx, getPolandInfo, params, header)
 }
 
+func (c *countriesClient) GetPolandInfoResponse(ctx context.Context, header http.Header) (*GetPolandInfoResponse, error) {
+	res, err := c.GetPolandInfo(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetPolandInfoResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetPolandInfoResponse struct {
 	Data   GetPolandInfoData `json:"data"`
 	Errors []GraphQLError    `json:"errors"`
@@ -70,8 +78,8 @@ type countriesClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) CountriesClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) CountriesClient {
 	return &countriesClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -45,37 +46,11 @@ type Volume struct {
 	CubicMeters int `json:"cubic_meters"`
 }
 
-const getBatchInfo = `query getBatchInfo($limit: Int) {
-    missions(limit: $limit) {
-        manufacturers
-    }
-    launchpads(limit: $limit) {
-        name
-        location {
-            name
-        }
-    }
-    roadster {
-        name
-        wikipedia
-    }
-    company {
-        ceo
-    }
-    dragons(limit: $limit) {
-        wikipedia
-        name
-        type
-        pressurized_capsule {
-            payload_volume {
-                cubic_meters
-            }
-        }
-    }
-}`
+const getBatchInfo = `query getBatchInfo($limit:Int){missions(limit:$limit){manufacturers}launchpads(limit:$limit){name location{name}}roadster{name wikipedia}company{ceo}dragons(limit:$limit){wikipedia name type pressurized_capsule{payload_volume{cubic_meters}}}}`
 
 type SpaceXClient interface {
 	GetBatchInfo(ctx context.Context, limit int, header http.Header) (*http.Response, error)
+	GetBatchInfoResponse(ctx context.Context, limit int, header http.Header) (*GetBatchInfoResponse, error)
 }
 
 func (c *spaceXClient) GetBatchInfo(ctx context.Context, limit int, header http.Header) (*http.Response, error) {
@@ -85,6 +60,22 @@ func (c *spaceXClient) GetBatchInfo(ctx context.Context, limit int, header http.
 	return c.ctrl.Execute(ctx, getBatchInfo, params, header)
 }
 
+func (c *spaceXClient) GetBatchInfoResponse(ctx context.Context, limit int, header http.Header) (*GetBatchInfoResponse, error) {
+	res, err := c.GetBatchInfo(ctx, limit, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetBatchInfoResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetBatchInfoResponse struct {
 	Data   GetBatchInfoData `json:"data"`
 	Errors []GraphQLError   `json:"errors"`
@@ -117,8 +108,8 @@ type spaceXClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) SpaceXClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) SpaceXClient {
 	return &spaceXClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"io"
+	"errors"
+	"github.com/Bartosz-D3V/grafik/client"
 	"log"
 	"net/http"
 	"time"
@@ -17,32 +17,17 @@ func main() {
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 	}
-	client := New(spacexUrl, httpClient)
+	c := New(spacexUrl, httpClient)
 
-	res, err := client.GetRocketResults(context.Background(), maxResults, nil)
-	if err != nil {
-		panic(err)
-	}
-
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			panic(err)
-		}
-	}(res.Body)
+	_, err := c.GetRocketResultsResponse(context.Background(), maxResults, nil)
 
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		panic(err)
-	}
-	var graphqlRes GetRocketResultsResponse
-	err = json.Unmarshal(b, &graphqlRes)
-	if err != nil {
+	var resErr client.GraphQLResponseError
+	if !errors.As(err, &resErr) {
 		panic(err)
 	}
 
-	log.Printf("Found %d errors in GraphQL response \n", len(graphqlRes.Errors))
-	for _, e := range graphqlRes.Errors {
-		log.Printf("Message=%s\nLine=%d\nColumn=%d\nCode=%s", e.Message, e.Locations[0].Line, e.Locations[0].Column, e.Extensions.Code)
+	log.Printf("Found %d errors in GraphQL response with HTTP status %d\n", len(resErr.Errors), resErr.StatusCode)
+	for _, e := range resErr.Errors {
+		log.Printf("Message=%s\nLine=%d\nColumn=%d\nCode=%v", e.Message, e.Locations[0].Line, e.Locations[0].Column, e.Extensions["code"])
 	}
 }
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -23,21 +24,11 @@ type RocketsResult struct {
 	Data   []Rocket `json:"data"`
 }
 
-const getRocketResults = `query getRocketResults($limit: Int){
-    rocketsResult(limit: $limit) {
-        result {
-            totalCount
-        }
-        data {
-            name
-            country
-            total_per_launch
-        }
-    }
-}`
+const getRocketResults = `query getRocketResults($limit:Int){rocketsResult(limit:$limit){result{totalCount}data{name country total_per_launch}}}`
 
 type SpaceXClient interface {
 	GetRocketResults(ctx context.Context, limit int, header http.Header) (*http.Response, error)
+	GetRocketResultsResponse(ctx context.Context, limit int, header http.Header) (*GetRocketResultsResponse, error)
 }
 
 func (c *spaceXClient) GetRocketResults(ctx context.Context, limit int, header http.Header) (*http.Response, error) {
@@ -47,6 +38,22 @@ func (c *spaceXClient) GetRocketResults(ctx context.Context, limit int, header h
 	return c.ctrl.Execute(ctx, getRocketResults, params, header)
 }
 
+func (c *spaceXClient) GetRocketResultsResponse(ctx context.Context, limit int, header http.Header) (*GetRocketResultsResponse, error) {
+	res, err := c.GetRocketResults(ctx, limit, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetRocketResultsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetRocketResultsResponse struct {
 	Data   GetRocketResultsData `json:"data"`
 	Errors []GraphQLError       `json:"errors"`
@@ -75,8 +82,8 @@ type spaceXClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) SpaceXClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) SpaceXClient {
 	return &spaceXClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	GraphqlClient "github.com/Bartosz-D3V/grafik/client"
 	"net/http"
 )
@@ -57,42 +58,11 @@ type UserConnection struct {
 	TotalCount int `json:"totalCount"`
 }
 
-const getData = `query getData {
-    viewer {
-        login
-        starredRepositories {
-            totalCount
-        }
-        repositories(first: 3) {
-            edges {
-                node {
-                    name
-                    languages(first: 5) {
-                        nodes {
-                            name,
-                            color
-                        }
-                    }
-                    stargazers {
-                        totalCount
-                    }
-                    forks {
-                        totalCount
-                    }
-                    watchers {
-                        totalCount
-                    }
-                    issues(states:[OPEN]) {
-                        totalCount
-                    }
-                }
-            }
-        }
-    }
-}`
+const getData = `query getData{viewer{login starredRepositories{totalCount}repositories(first:3){edges{node{name languages(first:5){nodes{name color}}stargazers{totalCount}forks{totalCount}watchers{totalCount}issues(states:[OPEN]){totalCount}}}}}}`
 
 type GithubClient interface {
 	GetData(ctx context.Context, header http.Header) (*http.Response, error)
+	GetDataResponse(ctx context.Context, header http.Header) (*GetDataResponse, error)
 }
 
 func (c *githubClient) GetData(ctx context.Context, header http.Header) (*http.Response, error) {
@@ -101,6 +71,22 @@ func (c *githubClient) GetData(ctx context.Context, header http.Header) (*http.R
 	return c.ctrl.Execute(ctx, getData, params, header)
 }
 
+func (c *githubClient) GetDataResponse(ctx context.Context, header http.Header) (*GetDataResponse, error) {
+	res, err := c.GetData(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out GetDataResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
 type GetDataResponse struct {
 	Data   GetDataData    `json:"data"`
 	Errors []GraphQLError `json:"errors"`
@@ -129,8 +115,8 @@ type githubClient struct {
 	ctrl GraphqlClient.Client
 }
 
-func New(endpoint string, client *http.Client) GithubClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) GithubClient {
 	return &githubClient{
-		ctrl: GraphqlClient.New(endpoint, client),
+		ctrl: GraphqlClient.New(endpoint, client, opts...),
 	}
 }
@@ -2,9 +2,7 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -35,24 +33,7 @@ func main() {
 	}
 	client := New(githubUrl, httpClient)
 
-	res, err := client.GetData(context.Background(), nil)
-	if err != nil {
-		panic(err)
-	}
-
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			panic(err)
-		}
-	}(res.Body)
-
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		panic(err)
-	}
-	var graphqlRes GetDataResponse
-	err = json.Unmarshal(b, &graphqlRes)
+	graphqlRes, err := client.GetDataResponse(context.Background(), nil)
 	if err != nil {
 		panic(err)
 	}
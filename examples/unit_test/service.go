@@ -2,9 +2,7 @@ package unit_test_with_grafik
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 )
 
 type service struct {
@@ -12,28 +10,11 @@ type service struct {
 }
 
 func (s service) ReturnAverageCostForPerLaunch() (int, error) {
-	res, err := s.client.GetRocketResults(context.Background(), 50, nil)
+	graphqlRes, err := s.client.GetRocketResultsResponse(context.Background(), 50, nil)
 	if err != nil {
 		return 0, fmt.Errorf("SpaceXClient failed: %s", err.Error())
 	}
 
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			panic(err)
-		}
-	}(res.Body)
-
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		return 0, err
-	}
-	var graphqlRes GetRocketResultsResponse
-	err = json.Unmarshal(b, &graphqlRes)
-	if err != nil {
-		return 0, err
-	}
-
 	numOfMissions := len(graphqlRes.Data.RocketsResult.Data)
 	totalCost := 0
 	for _, rocket := range graphqlRes.Data.RocketsResult.Data {
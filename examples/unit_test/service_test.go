@@ -49,6 +49,14 @@ func (c mockSpaceXClient) GetRocketResults(context.Context, int, http.Header) (*
 	return nil, errors.New("GraphQL call failed")
 }
 
+func (c mockSpaceXClient) GetRocketResultsResponse(context.Context, int, http.Header) (*GetRocketResultsResponse, error) {
+	if c.returnValidResponse {
+		res := createGraphQLResponse()
+		return &res, nil
+	}
+	return nil, errors.New("GraphQL call failed")
+}
+
 func createGraphQLResponse() GetRocketResultsResponse {
 	return GetRocketResultsResponse{
 		Data: GetRocketResultsData{
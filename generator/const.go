@@ -6,3 +6,6 @@ const Header = "// Generated with grafik. DO NOT EDIT"
 
 // GraphQLErrorStructName - default name of the generated GraphQL error response struct.
 const GraphQLErrorStructName = "GraphQLError"
+
+// NullFieldsName - name of the field of generated GraphQL input struct listing fields to be sent as explicit null.
+const NullFieldsName = "NullFields"
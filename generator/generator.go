@@ -11,25 +11,35 @@ import (
 	"go/parser"
 	"go/token"
 	"io"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
 
 // A Generator is an interface that provides contract for generator struct and is being used instead of a pointer.
 type Generator interface {
-	WriteHeader()
-	WritePackage(pkgName string)
-	WriteImports()
-	WriteLineBreak(r int)
-	WriteInterface(name string, fn ...ds.Func)
-	WritePublicStruct(s ds.Struct, usePointers bool)
-	WritePrivateStruct(s ds.Struct)
-	WriteEnum(e ds.Enum)
-	WriteConst(c ds.Const)
-	WriteClientConstructor(clientName string)
-	WriteInterfaceImplementation(clientName string, f ds.Func)
-	WriteGraphqlErrorStructs(usePointers bool)
-	Generate() io.WriterTo
+	WriteHeader() error
+	WritePackage(pkgName string) error
+	WriteImports(imports ...ds.Import) error
+	WriteLineBreak(r int) error
+	WriteInterface(name string, fn ...ds.Func) error
+	WriteTypeAlias(name string, goType string) error
+	WritePublicStruct(s ds.Struct, usePointers bool) error
+	WritePrivateStruct(s ds.Struct) error
+	WriteEnum(e ds.Enum) error
+	WriteConst(c ds.Const) error
+	WriteClientConstructor(clientName string) error
+	WriteInterfaceImplementation(clientName string, f ds.Func) error
+	WriteTypedInterfaceImplementation(clientName string, f ds.Func, responseName string) error
+	WriteSubscriptionImplementation(clientName string, f ds.Func, eventName string) error
+	WriteInputMarshaller(s ds.Struct, optionalFields []ds.TypeField) error
+	WriteUnion(u ds.Union) error
+	WriteEmbeddedUnmarshaller(s ds.Struct, paths []string) error
+	WriteEmbeddedMarshaller(s ds.Struct, paths []string) error
+	WriteGraphqlErrorStructs(usePointers bool, nullablePointers bool) error
+	WriteDeclaration(code string) error
+	Generate() (io.WriterTo, error)
 }
 
 //go:embed templates/*
@@ -49,7 +59,14 @@ type generator struct {
 }
 
 // New return instance of generator.
-func New() Generator {
+func New() (Generator, error) {
+	return NewWithTemplateDir("")
+}
+
+// NewWithTemplateDir returns instance of generator with embedded templates overridden by *.tmpl files of templateDir.
+// Each file overrides embedded template of the same name (i.e. struct.tmpl) and receives the same data - see template_data.go.
+// Embedded templates are used if templateDir is empty.
+func NewWithTemplateDir(templateDir string) (Generator, error) {
 	funcMap := template.FuncMap{
 		"title":        strings.Title,
 		"sentenceCase": common.SentenceCase,
@@ -58,143 +75,324 @@ func New() Generator {
 
 	tmpl, err := template.New("codeTemplate").Funcs(funcMap).ParseFS(content, "**/*.tmpl")
 	if err != nil {
-		panic(fmt.Errorf("failed to parse templates. Cause: %w", err))
+		return nil, fmt.Errorf("failed to parse templates. Cause: %w", err)
+	}
+	if templateDir != "" {
+		tmpl, err = overrideTemplates(tmpl, templateDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse custom templates. Cause: %w", err)
+		}
 	}
 	return &generator{
 		stream:   &bytes.Buffer{},
 		template: tmpl.Funcs(funcMap),
-	}
+	}, nil
 }
 
 // WriteHeader writes top level comment (grafik header).
-func (g *generator) WriteHeader() {
+func (g *generator) WriteHeader() error {
 	_, err := g.stream.WriteString(Header)
 	if err != nil {
-		panic(fmt.Errorf("failed to write header. Cause: %w", err))
+		return fmt.Errorf("failed to write header. Cause: %w", err)
 	}
+	return nil
 }
 
 // WritePackage writes name of the package.
-func (g *generator) WritePackage(pkgName string) {
+func (g *generator) WritePackage(pkgName string) error {
 	_, err := g.stream.WriteString(fmt.Sprintf("package %s", pkgName))
 	if err != nil {
-		panic(fmt.Errorf("failed to write package name. Cause: %w", err))
+		return fmt.Errorf("failed to write package name. Cause: %w", err)
 	}
+	return nil
 }
 
-// WriteImports writes list of all required imports.
-func (g *generator) WriteImports() {
-	err := g.template.ExecuteTemplate(g.stream, "imports.tmpl", make(map[string]interface{}))
+// WriteImports writes list of all imports required by grafik client with additional imports sorted by path.
+func (g *generator) WriteImports(imports ...ds.Import) error {
+	allImports := []ds.Import{
+		{Path: "context"},
+		{Alias: "GraphqlClient", Path: "github.com/Bartosz-D3V/grafik/client"},
+		{Path: "net/http"},
+	}
+	for _, imp := range imports {
+		if !containsImport(allImports, imp.Path) {
+			allImports = append(allImports, imp)
+		}
+	}
+	sort.Slice(allImports, func(i, j int) bool {
+		return allImports[i].Path < allImports[j].Path
+	})
+
+	config := ImportsData{
+		Imports: allImports,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "imports.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'imports' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'imports' template. Cause: %w", err)
 	}
+	return nil
 }
 
 // WriteLineBreak writes number of line breaks based on provided number (r).
-func (g *generator) WriteLineBreak(r int) {
+func (g *generator) WriteLineBreak(r int) error {
 	_, err := g.stream.WriteString(strings.Repeat("\n", r))
 	if err != nil {
-		panic(fmt.Errorf("failed to write line break. Cause: %w", err))
+		return fmt.Errorf("failed to write line break. Cause: %w", err)
 	}
+	return nil
 }
 
 // WriteInterface writes interface of provided name and functions (fn).
-func (g *generator) WriteInterface(name string, fn ...ds.Func) {
-	config := map[string]interface{}{
-		"InterfaceName": name,
-		"Functions":     fn,
+func (g *generator) WriteInterface(name string, fn ...ds.Func) error {
+	config := InterfaceData{
+		InterfaceName: name,
+		Functions:     fn,
 	}
 	err := g.template.ExecuteTemplate(g.stream, "interface.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'interface' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'interface' template. Cause: %w", err)
 	}
+	return nil
+}
+
+// WriteTypeAlias writes type alias of provided name to goType - i.e. type DateTime = time.Time.
+func (g *generator) WriteTypeAlias(name string, goType string) error {
+	config := TypeAliasData{
+		Name: name,
+		Type: goType,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "type_alias.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'type_alias' template. Cause: %w", err)
+	}
+	return nil
 }
 
 // WritePublicStruct writes struct with capitalized name, fields and json tags based on generator.Struct.
-func (g *generator) WritePublicStruct(s ds.Struct, usePointers bool) {
-	config := map[string]interface{}{
-		"Struct":      s,
-		"Public":      true,
-		"UsePointers": usePointers,
+func (g *generator) WritePublicStruct(s ds.Struct, usePointers bool) error {
+	config := StructData{
+		Struct:      s,
+		Public:      true,
+		UsePointers: usePointers,
 	}
 	err := g.template.ExecuteTemplate(g.stream, "struct.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'struct' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'struct' template. Cause: %w", err)
 	}
+	return nil
 }
 
 // WritePrivateStruct writes struct with lowercase name, fields and no json tags based on generator.Struct.
-func (g *generator) WritePrivateStruct(s ds.Struct) {
-	config := map[string]interface{}{
-		"Struct": s,
-		"Public": false,
+func (g *generator) WritePrivateStruct(s ds.Struct) error {
+	config := StructData{
+		Struct: s,
+		Public: false,
 	}
 	err := g.template.ExecuteTemplate(g.stream, "struct.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'struct' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'struct' template. Cause: %w", err)
 	}
+	return nil
 }
 
 // WriteEnum writes Enum based on generator.Enum.
-func (g *generator) WriteEnum(e ds.Enum) {
+func (g *generator) WriteEnum(e ds.Enum) error {
 	err := g.template.ExecuteTemplate(g.stream, "enum.tmpl", e)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'enum' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'enum' template. Cause: %w", err)
 	}
+	return nil
 }
 
 // WriteConst writes Const based on generator.Const.
-func (g *generator) WriteConst(c ds.Const) {
+func (g *generator) WriteConst(c ds.Const) error {
 	err := g.template.ExecuteTemplate(g.stream, "const.tmpl", c)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'const' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'const' template. Cause: %w", err)
 	}
+	return nil
 }
 
 // WriteClientConstructor writes New function that serves as a constructor for grafik client.
-func (g *generator) WriteClientConstructor(clientName string) {
-	err := g.template.ExecuteTemplate(g.stream, "constructor.tmpl", clientName)
+func (g *generator) WriteClientConstructor(clientName string) error {
+	config := ConstructorData{
+		ClientName: clientName,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "constructor.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'constructor' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'constructor' template. Cause: %w", err)
 	}
+	return nil
 }
 
 // WriteInterfaceImplementation writes implementation for earlier defined interface as function with receiver.
-func (g *generator) WriteInterfaceImplementation(clientName string, f ds.Func) {
-	config := map[string]interface{}{
-		"ClientName": clientName,
-		"Func":       f,
+func (g *generator) WriteInterfaceImplementation(clientName string, f ds.Func) error {
+	config := InterfaceImplData{
+		ClientName: clientName,
+		Func:       f,
 	}
 	err := g.template.ExecuteTemplate(g.stream, "interface_impl.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'interface_impl' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'interface_impl' template. Cause: %w", err)
+	}
+	return nil
+}
+
+// WriteTypedInterfaceImplementation writes implementation of the method that executes the operation and decodes the response into responseName struct.
+func (g *generator) WriteTypedInterfaceImplementation(clientName string, f ds.Func, responseName string) error {
+	config := TypedInterfaceImplData{
+		ClientName:   clientName,
+		Func:         f,
+		ResponseName: responseName,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "interface_typed_impl.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'interface_typed_impl' template. Cause: %w", err)
+	}
+	return nil
+}
+
+// WriteSubscriptionImplementation writes implementation of the method that starts GraphQL subscription and decodes each event into eventName struct.
+func (g *generator) WriteSubscriptionImplementation(clientName string, f ds.Func, eventName string) error {
+	config := SubscriptionImplData{
+		ClientName: clientName,
+		Func:       f,
+		EventName:  eventName,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "subscription_impl.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'subscription_impl' template. Cause: %w", err)
+	}
+	return nil
+}
+
+// WriteInputMarshaller writes MarshalJSON function of input struct that omits unset optional fields and sends explicit nulls.
+func (g *generator) WriteInputMarshaller(s ds.Struct, optionalFields []ds.TypeField) error {
+	config := InputMarshallerData{
+		Struct:         s,
+		OptionalFields: optionalFields,
+		NullFieldsName: NullFieldsName,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "input_marshal.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'input_marshal' template. Cause: %w", err)
+	}
+	return nil
+}
+
+// WriteUnion writes Go interface implemented by all members of the union and the struct decoding its value based on __typename.
+func (g *generator) WriteUnion(u ds.Union) error {
+	config := UnionData{
+		Union: u,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "union.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'union' template. Cause: %w", err)
 	}
+	return nil
+}
+
+// WriteEmbeddedUnmarshaller writes UnmarshalJSON function of struct embedding structs of named fragments.
+func (g *generator) WriteEmbeddedUnmarshaller(s ds.Struct, paths []string) error {
+	config := EmbeddedUnmarshallerData{
+		Struct: s,
+		Paths:  paths,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "embedded_unmarshal.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'embedded_unmarshal' template. Cause: %w", err)
+	}
+	return nil
+}
+
+// WriteEmbeddedMarshaller writes MarshalJSON function of struct embedding structs of named fragments.
+func (g *generator) WriteEmbeddedMarshaller(s ds.Struct, paths []string) error {
+	config := EmbeddedMarshallerData{
+		Struct: s,
+		Paths:  paths,
+	}
+	err := g.template.ExecuteTemplate(g.stream, "embedded_marshal.tmpl", config)
+	if err != nil {
+		return fmt.Errorf("failed to execute 'embedded_marshal' template. Cause: %w", err)
+	}
+	return nil
 }
 
 // WriteGraphqlErrorStructs writes predefined GraphQL error structs.
-func (g *generator) WriteGraphqlErrorStructs(usePointers bool) {
-	config := map[string]interface{}{
-		"UsePointers":            usePointers,
-		"GraphQLErrorStructName": GraphQLErrorStructName,
+// If nullablePointers is true only fields that can be omitted as per GraphQL specification are generated as pointers.
+func (g *generator) WriteGraphqlErrorStructs(usePointers bool, nullablePointers bool) error {
+	config := GraphqlErrorData{
+		UsePointers:            usePointers,
+		NullablePointers:       nullablePointers,
+		GraphQLErrorStructName: GraphQLErrorStructName,
 	}
 	err := g.template.ExecuteTemplate(g.stream, "graphql_error.tmpl", config)
 	if err != nil {
-		panic(fmt.Errorf("failed to execute 'graphql_error' template. Cause: %w", err))
+		return fmt.Errorf("failed to execute 'graphql_error' template. Cause: %w", err)
+	}
+	return nil
+}
+
+// WriteDeclaration writes Go declaration as it is - i.e. method added by plugin.
+func (g *generator) WriteDeclaration(code string) error {
+	_, err := g.stream.WriteString(code)
+	if err != nil {
+		return fmt.Errorf("failed to write declaration. Cause: %w", err)
 	}
+	return nil
 }
 
 // Generate formats the generated code and returns it as a WriterTo interface.
-func (g *generator) Generate() io.WriterTo {
+func (g *generator) Generate() (io.WriterTo, error) {
 	writer := &bytes.Buffer{}
 	fSet := token.NewFileSet()
 	f, err := parser.ParseFile(fSet, "", g.stream, parser.ParseComments)
 	if err != nil {
-		panic(fmt.Errorf("failed to parse generated Go code. Cause: %w", err))
+		return nil, fmt.Errorf("failed to parse generated Go code. Cause: %w", err)
 	}
 
 	err = format.Node(writer, fSet, f)
 	if err != nil {
-		panic(fmt.Errorf("failed to gofmt generated Go code. Cause: %w", err))
+		return nil, fmt.Errorf("failed to gofmt generated Go code. Cause: %w", err)
+	}
+	return writer, nil
+}
+
+// overrideTemplates parses all *.tmpl files of dir replacing embedded templates of the same name.
+func overrideTemplates(tmpl *template.Template, dir string) (*template.Template, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.tmpl files found in %s", dir)
+	}
+	for _, f := range files {
+		if tmpl.Lookup(filepath.Base(f)) == nil {
+			return nil, fmt.Errorf("unknown template %s - expected one of %s", filepath.Base(f), strings.Join(templateNames(tmpl), ", "))
+		}
+	}
+	return tmpl.ParseFiles(files...)
+}
+
+// templateNames returns sorted names of all embedded template files.
+func templateNames(tmpl *template.Template) []string {
+	names := make([]string, 0)
+	for _, t := range tmpl.Templates() {
+		if strings.HasSuffix(t.Name(), ".tmpl") {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// containsImport returns true if imports contain import with given path.
+func containsImport(imports []ds.Import, path string) bool {
+	for _, imp := range imports {
+		if imp.Path == path {
+			return true
+		}
 	}
-	return writer
+	return false
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/Bartosz-D3V/grafik/ds"
 	"github.com/Bartosz-D3V/grafik/test"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 	"text/template"
 )
@@ -14,7 +16,7 @@ import (
 func TestGenerator_WriteHeader(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WriteHeader()
 	g.WriteLineBreak(2)
@@ -36,7 +38,7 @@ func TestGenerator_WriteHeader_Error(t *testing.T) {
 
 	g := generator{stream: faultyWriter{}}
 
-	assert.PanicsWithError(t, "failed to write header. Cause: unit test: Failed to write a string", g.WriteHeader)
+	assert.EqualError(t, g.WriteHeader(), "failed to write header. Cause: unit test: Failed to write a string")
 }
 
 func TestGenerator_WritePackage_Error(t *testing.T) {
@@ -44,9 +46,7 @@ func TestGenerator_WritePackage_Error(t *testing.T) {
 
 	g := generator{stream: faultyWriter{}}
 
-	assert.PanicsWithError(t, "failed to write package name. Cause: unit test: Failed to write a string", func() {
-		g.WritePackage("")
-	})
+	assert.EqualError(t, g.WritePackage(""), "failed to write package name. Cause: unit test: Failed to write a string")
 }
 
 func TestGenerator_WriteLineBreak_Error(t *testing.T) {
@@ -54,15 +54,13 @@ func TestGenerator_WriteLineBreak_Error(t *testing.T) {
 
 	g := generator{stream: faultyWriter{}}
 
-	assert.PanicsWithError(t, "failed to write line break. Cause: unit test: Failed to write a string", func() {
-		g.WriteLineBreak(1)
-	})
+	assert.EqualError(t, g.WriteLineBreak(1), "failed to write line break. Cause: unit test: Failed to write a string")
 }
 
 func TestGenerator_WriteImports(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -83,6 +81,32 @@ import (
 	assert.Equal(t, expOut, out)
 }
 
+func TestGenerator_WriteImports_AdditionalImports(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	g.WriteImports(ds.Import{Path: "time"}, ds.Import{Path: "encoding/json"}, ds.Import{Path: "net/http"})
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+import (
+    "context"
+    "encoding/json"
+    GraphqlClient "github.com/Bartosz-D3V/grafik/client"
+    "net/http"
+    "time"
+)
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
 func TestGenerator_WriteImports_Error(t *testing.T) {
 	t.Parallel()
 
@@ -92,9 +116,7 @@ func TestGenerator_WriteImports_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'imports' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteImports()
-	})
+	assert.EqualError(t, g.WriteImports(), "failed to execute 'imports' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WriteInterface_NoArgWithReturn(t *testing.T) {
@@ -105,7 +127,7 @@ func TestGenerator_WriteInterface_NoArgWithReturn(t *testing.T) {
 		Type: "Book",
 	}
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -132,7 +154,7 @@ func TestGenerator_WriteInterface_SingleArgWithReturn(t *testing.T) {
 		Type: "Book",
 	}
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -163,7 +185,7 @@ func TestGenerator_WriteInterface_MultiArgWithReturn(t *testing.T) {
 		Type: "Employee",
 	}
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -199,7 +221,7 @@ func TestGenerator_WriteInterface_MultiMethods(t *testing.T) {
 		Type: "Employee",
 	}
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -228,15 +250,45 @@ func TestGenerator_WriteInterface_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'interface' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteInterface("")
-	})
+	assert.EqualError(t, g.WriteInterface(""), "failed to execute 'interface' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteTypeAlias(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	g.WriteTypeAlias("dateTime", "time.Time")
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+type DateTime = time.Time
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteTypeAlias_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("type_alias.tmpl").Parse("type_alias.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteTypeAlias("", ""), "failed to execute 'type_alias' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WritePublicStruct(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -274,7 +326,7 @@ type Person struct {
 func TestGenerator_WritePublicStruct_WithPointers(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -318,15 +370,13 @@ func TestGenerator_WritePublicStruct_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'struct' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WritePublicStruct(ds.Struct{}, false)
-	})
+	assert.EqualError(t, g.WritePublicStruct(ds.Struct{}, false), "failed to execute 'struct' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WritePrivateStruct(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -368,15 +418,13 @@ func TestGenerator_WritePrivateStruct_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'struct' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WritePrivateStruct(ds.Struct{})
-	})
+	assert.EqualError(t, g.WritePrivateStruct(ds.Struct{}), "failed to execute 'struct' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WriteEnum(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -412,15 +460,13 @@ func TestGenerator_WriteEnum_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'enum' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteEnum(ds.Enum{})
-	})
+	assert.EqualError(t, g.WriteEnum(ds.Enum{}), "failed to execute 'enum' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WriteConst(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -460,15 +506,13 @@ func TestGenerator_WriteConst_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'const' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteConst(ds.Const{})
-	})
+	assert.EqualError(t, g.WriteConst(ds.Const{}), "failed to execute 'const' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WriteClientConstructor(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -479,9 +523,9 @@ func TestGenerator_WriteClientConstructor(t *testing.T) {
 	expOut := test.PrepExpCode(t, `
 package test
 
-func New(endpoint string, client *http.Client) ApiClient {
+func New(endpoint string, client *http.Client, opts ...GraphqlClient.Option) ApiClient {
     return &apiClient {
-        ctrl: GraphqlClient.New(endpoint, client),
+        ctrl: GraphqlClient.New(endpoint, client, opts...),
     }
 }
 `)
@@ -498,15 +542,13 @@ func TestGenerator_WriteClientConstructor_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'constructor' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteClientConstructor("")
-	})
+	assert.EqualError(t, g.WriteClientConstructor(""), "failed to execute 'constructor' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WriteInterfaceImplementation(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
@@ -537,6 +579,75 @@ func (c *apiClient) CountResults(ctx context.Context, condition string, header h
 	assert.Equal(t, expOut, out)
 }
 
+func TestGenerator_WriteInterfaceImplementation_Persisted(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	f := ds.Func{
+		Name: "countResults",
+		Args: []ds.TypeArg{{
+			Name: "condition",
+			Type: "string",
+		}},
+		Type:      "int",
+		Persisted: true,
+	}
+	g.WriteInterfaceImplementation("apiClient", f)
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (c *apiClient) CountResults(ctx context.Context, condition string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["condition"] = condition
+
+	return c.ctrl.ExecutePersisted(ctx, countResults, countResultsHash, params, header)
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteInterfaceImplementation_PersistedMutation(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	f := ds.Func{
+		Name: "launchRocket",
+		Args: []ds.TypeArg{{
+			Name: "id",
+			Type: "string",
+		}},
+		Type:      "int",
+		Mutation:  true,
+		Persisted: true,
+	}
+	g.WriteInterfaceImplementation("apiClient", f)
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (c *apiClient) LaunchRocket(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	return c.ctrl.ExecutePersistedMutation(ctx, launchRocket, launchRocketHash, params, header)
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
 func TestGenerator_WriteInterfaceImplementation_Error(t *testing.T) {
 	t.Parallel()
 
@@ -546,20 +657,366 @@ func TestGenerator_WriteInterfaceImplementation_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'interface_impl' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteInterfaceImplementation("", ds.Func{})
-	})
+	assert.EqualError(t, g.WriteInterfaceImplementation("", ds.Func{}), "failed to execute 'interface_impl' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteTypedInterfaceImplementation(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	f := ds.Func{
+		Name: "countResults",
+		Args: []ds.TypeArg{{
+			Name: "condition",
+			Type: "string",
+		}},
+		Type:         "(*http.Response, error)",
+		WrapperTypes: nil,
+	}
+	g.WriteTypedInterfaceImplementation("apiClient", f, "CountResultsResponse")
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (c *apiClient) CountResultsResponse(ctx context.Context, condition string, header http.Header) (*CountResultsResponse, error) {
+	res, err := c.CountResults(ctx, condition, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out CountResultsResponse
+	if err := GraphqlClient.DecodeResponse(res, &out); err != nil {
+		if errors.As(err, &GraphqlClient.GraphQLResponseError{}) {
+			return &out, err
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteTypedInterfaceImplementation_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("interface_typed_impl.tmpl").Parse("interface_typed_impl.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteTypedInterfaceImplementation("", ds.Func{}, ""), "failed to execute 'interface_typed_impl' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteSubscriptionImplementation(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	f := ds.Func{
+		Name: "onLaunch",
+		Args: []ds.TypeArg{{
+			Name: "id",
+			Type: "string",
+		}},
+		Type:         "(<-chan OnLaunchEvent, error)",
+		WrapperTypes: nil,
+	}
+	g.WriteSubscriptionImplementation("apiClient", f, "OnLaunchEvent")
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (c *apiClient) OnLaunch(ctx context.Context, id string, header http.Header) (<-chan OnLaunchEvent, error) {
+	params := make(map[string]interface{}, 1)
+	params["id"] = id
+
+	msgs, err := c.ctrl.Subscribe(ctx, onLaunch, params, header)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan OnLaunchEvent)
+	go func() {
+		defer close(events)
+		for msg := range msgs {
+			var event OnLaunchEvent
+			if event.Err = msg.Err; event.Err == nil {
+				event.Err = GraphqlClient.DecodePayload(msg.Payload, &event)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteSubscriptionImplementation_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("subscription_impl.tmpl").Parse("subscription_impl.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteSubscriptionImplementation("", ds.Func{}, ""), "failed to execute 'subscription_impl' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteInputMarshaller(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	s := ds.Struct{
+		Name: "rocketInput",
+		Fields: []ds.TypeField{
+			{Name: "id", Type: "string", JsonName: "id", NonNull: true},
+			{Name: "name", Type: "*string", JsonName: "name"},
+			{Name: "tags", Type: "[]string", JsonName: "tags"},
+		},
+	}
+	g.WriteInputMarshaller(s, s.Fields[1:])
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (i RocketInput) MarshalJSON() ([]byte, error) {
+	type alias RocketInput
+	unset := map[string]bool{
+		"name": i.Name == nil,
+		"tags": i.Tags == nil,
+	}
+	return GraphqlClient.MarshalInput(alias(i), unset, i.NullFields)
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteInputMarshaller_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("input_marshal.tmpl").Parse("input_marshal.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteInputMarshaller(ds.Struct{}, nil), "failed to execute 'input_marshal' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteUnion(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	u := ds.Union{
+		Name:          "CharacterFragment",
+		InterfaceName: "Character",
+		Members: []ds.UnionMember{
+			{TypeName: "Human", StructName: "CharacterHuman"},
+			{TypeName: "Droid", StructName: "CharacterDroid"},
+		},
+	}
+	g.WriteUnion(u)
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+package test
+
+type Character interface {
+	isCharacter()
+}
+
+func (CharacterHuman) isCharacter() {}
+
+func (CharacterDroid) isCharacter() {}
+
+type CharacterFragment struct {
+	Value Character
+}
+
+func (u *CharacterFragment) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		u.Value = nil
+		return nil
+	}
+	var typename struct {
+		TypeName string %[1]cjson:"__typename"%[1]c
+	}
+	if err := json.Unmarshal(data, &typename); err != nil {
+		return err
+	}
+	switch typename.TypeName {
+	case "Human":
+		var v CharacterHuman
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	case "Droid":
+		var v CharacterDroid
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		u.Value = v
+		return nil
+	}
+	return fmt.Errorf("unexpected __typename %%q of CharacterFragment", typename.TypeName)
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteUnion_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("union.tmpl").Parse("union.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteUnion(ds.Union{}), "failed to execute 'union' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteEmbeddedUnmarshaller(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	s := ds.Struct{
+		Name: "rocket",
+		Fields: []ds.TypeField{
+			{Name: "rocketInfo", Type: "rocketInfo", Embedded: true},
+			{Name: "id", Type: "string", JsonName: "id"},
+		},
+	}
+	g.WritePublicStruct(s, false)
+	g.WriteLineBreak(2)
+	g.WriteEmbeddedUnmarshaller(s, []string{"RocketInfo", "RocketInfo.RocketIds"})
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+package test
+
+type Rocket struct {
+	RocketInfo %[1]cjson:"-"%[1]c
+	Id         string %[1]cjson:"id"%[1]c
+}
+
+func (s *Rocket) UnmarshalJSON(data []byte) error {
+	type alias Rocket
+	if err := json.Unmarshal(data, (*alias)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketInfo); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.RocketInfo.RocketIds); err != nil {
+		return err
+	}
+	return nil
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteEmbeddedUnmarshaller_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("embedded_unmarshal.tmpl").Parse("embedded_unmarshal.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteEmbeddedUnmarshaller(ds.Struct{}, nil), "failed to execute 'embedded_unmarshal' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteEmbeddedMarshaller(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	s := ds.Struct{
+		Name: "rocket",
+		Fields: []ds.TypeField{
+			{Name: "rocketInfo", Type: "rocketInfo", Embedded: true},
+			{Name: "id", Type: "string", JsonName: "id"},
+		},
+	}
+	g.WriteEmbeddedMarshaller(s, []string{"RocketInfo", "RocketInfo.RocketIds"})
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (s Rocket) MarshalJSON() ([]byte, error) {
+	type alias Rocket
+	return GraphqlClient.MarshalFragments(alias(s), s.RocketInfo, s.RocketInfo.RocketIds)
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteEmbeddedMarshaller_Error(t *testing.T) {
+	t.Parallel()
+
+	pTmpl, _ := template.New("embedded_marshal.tmpl").Parse("embedded_marshal.tmpl")
+	g := generator{
+		stream:   faultyWriter{},
+		template: pTmpl,
+	}
+
+	assert.EqualError(t, g.WriteEmbeddedMarshaller(ds.Struct{}, nil), "failed to execute 'embedded_marshal' template. Cause: unit test: Failed to write a slice of bytes")
 }
 
 func TestGenerator_WriteGraphqlErrorStructs(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
 
-	g.WriteGraphqlErrorStructs(false)
+	g.WriteGraphqlErrorStructs(false, false)
 
 	out := getSourceString(t, g)
 	expOut := test.PrepExpCode(t, fmt.Sprintf(`
@@ -587,12 +1044,12 @@ type GraphQLErrorExtensions struct {
 func TestGenerator_WriteGraphqlErrorStructs_WithPointers(t *testing.T) {
 	t.Parallel()
 
-	g := New()
+	g := newGenerator(t)
 
 	g.WritePackage("test")
 	g.WriteLineBreak(2)
 
-	g.WriteGraphqlErrorStructs(true)
+	g.WriteGraphqlErrorStructs(true, false)
 
 	out := getSourceString(t, g)
 	expOut := test.PrepExpCode(t, fmt.Sprintf(`
@@ -617,6 +1074,39 @@ type GraphQLErrorExtensions struct {
 	assert.Equal(t, expOut, out)
 }
 
+func TestGenerator_WriteGraphqlErrorStructs_WithNullablePointers(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	g.WriteGraphqlErrorStructs(false, true)
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, fmt.Sprintf(`
+package test
+
+type GraphQLError struct {
+	Message    string                  %[1]cjson:"message"%[1]c
+	Locations  []GraphQLErrorLocation  %[1]cjson:"locations"%[1]c
+	Extensions *GraphQLErrorExtensions %[1]cjson:"extensions"%[1]c
+}
+
+type GraphQLErrorLocation struct {
+	Line   int %[1]cjson:"line"%[1]c
+	Column int %[1]cjson:"column"%[1]c
+}
+
+type GraphQLErrorExtensions struct {
+	Code *string %[1]cjson:"code"%[1]c
+}
+`, '`'))
+
+	assert.Equal(t, expOut, out)
+}
+
 func TestGenerator_WriteGraphqlErrorStructs_Error(t *testing.T) {
 	t.Parallel()
 
@@ -626,15 +1116,114 @@ func TestGenerator_WriteGraphqlErrorStructs_Error(t *testing.T) {
 		template: pTmpl,
 	}
 
-	assert.PanicsWithError(t, "failed to execute 'graphql_error' template. Cause: unit test: Failed to write a slice of bytes", func() {
-		g.WriteGraphqlErrorStructs(false)
-	})
+	assert.EqualError(t, g.WriteGraphqlErrorStructs(false, false), "failed to execute 'graphql_error' template. Cause: unit test: Failed to write a slice of bytes")
+}
+
+func TestGenerator_WriteDeclaration(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	g.WriteDeclaration("func (r Rocket) String() string {\nreturn r.Name\n}")
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+func (r Rocket) String() string {
+	return r.Name
+}
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_WriteDeclaration_Error(t *testing.T) {
+	t.Parallel()
+
+	g := generator{
+		stream: faultyWriter{},
+	}
+
+	assert.EqualError(t, g.WriteDeclaration("var x = 1"), "failed to write declaration. Cause: unit test: Failed to write a string")
+}
+
+func TestGenerator_Generate_Error(t *testing.T) {
+	t.Parallel()
+
+	g := newGenerator(t)
+	assert.NoError(t, g.WritePackage("1test"))
+
+	_, err := g.Generate()
+	assert.EqualError(t, err, "failed to parse generated Go code. Cause: 1:9: expected 'IDENT', found 1")
+}
+
+func TestGenerator_NewWithTemplateDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tmpl := "// New{{.ClientName}} creates {{.ClientName}}.\nfunc New{{.ClientName}}(endpoint string) {{title .ClientName}} {\n    return &{{sentenceCase .ClientName}}{ctrl: GraphqlClient.New(endpoint, http.DefaultClient)}\n}"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "constructor.tmpl"), []byte(tmpl), 0600))
+
+	g, err := NewWithTemplateDir(dir)
+	assert.NoError(t, err)
+
+	g.WritePackage("test")
+	g.WriteLineBreak(2)
+
+	g.WriteClientConstructor("ApiClient")
+	g.WriteLineBreak(2)
+
+	g.WriteTypeAlias("DateTime", "string")
+
+	out := getSourceString(t, g)
+	expOut := test.PrepExpCode(t, `
+package test
+
+// NewApiClient creates ApiClient.
+func NewApiClient(endpoint string) ApiClient {
+    return &apiClient{ctrl: GraphqlClient.New(endpoint, http.DefaultClient)}
+}
+
+type DateTime = string
+`)
+
+	assert.Equal(t, expOut, out)
+}
+
+func TestGenerator_NewWithTemplateDir_Error(t *testing.T) {
+	t.Parallel()
+
+	emptyDir := t.TempDir()
+	_, err := NewWithTemplateDir(emptyDir)
+	assert.EqualError(t, err, fmt.Sprintf("failed to parse custom templates. Cause: no *.tmpl files found in %s", emptyDir))
+
+	unknownDir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(unknownDir, "client.tmpl"), []byte("{{.}}"), 0600))
+	_, err = NewWithTemplateDir(unknownDir)
+	assert.EqualError(t, err, "failed to parse custom templates. Cause: unknown template client.tmpl - expected one of const.tmpl, constructor.tmpl, embedded_marshal.tmpl, embedded_unmarshal.tmpl, enum.tmpl, graphql_error.tmpl, imports.tmpl, input_marshal.tmpl, interface.tmpl, interface_impl.tmpl, interface_typed_impl.tmpl, struct.tmpl, subscription_impl.tmpl, type_alias.tmpl, union.tmpl")
+
+	invalidDir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(invalidDir, "struct.tmpl"), []byte("{{.Struct"), 0600))
+	_, err = NewWithTemplateDir(invalidDir)
+	assert.EqualError(t, err, "failed to parse custom templates. Cause: template: struct.tmpl:1: unclosed action")
+}
+
+func newGenerator(t *testing.T) Generator {
+	g, err := New()
+	assert.NoError(t, err)
+
+	return g
 }
 
 func getSourceString(t *testing.T, g Generator) string {
-	fileContent := g.Generate()
+	fileContent, err := g.Generate()
+	assert.NoError(t, err)
 	src := &bytes.Buffer{}
-	_, err := fileContent.WriteTo(src)
+	_, err = fileContent.WriteTo(src)
 	assert.NoError(t, err)
 
 	return src.String()
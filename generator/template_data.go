@@ -0,0 +1,102 @@
+// Package generator abstracts writing specific Go constructs (interfaces, structs etc) into IO.
+package generator
+
+import "github.com/Bartosz-D3V/grafik/ds"
+
+// Data passed to each template is a stable contract of custom templates - fields are only added, never renamed or removed.
+// enum.tmpl and const.tmpl receive ds.Enum and ds.Const respectively.
+
+// ImportsData is passed to imports.tmpl.
+// Imports is a list of all imports required by grafik client sorted by path.
+type ImportsData struct {
+	Imports []ds.Import
+}
+
+// InterfaceData is passed to interface.tmpl.
+// InterfaceName is the name of grafik client interface.
+// Functions is a list of methods of the interface - each one is rendered with function_header template defined in interface.tmpl.
+type InterfaceData struct {
+	InterfaceName string
+	Functions     []ds.Func
+}
+
+// TypeAliasData is passed to type_alias.tmpl.
+// Name is the name of the alias and Type is aliased Go type - i.e. DateTime and time.Time.
+type TypeAliasData struct {
+	Name string
+	Type string
+}
+
+// StructData is passed to struct.tmpl.
+// Public specifies if the struct is exported with json tags (response and input types) or private (client implementation).
+// UsePointers specifies if all fields are generated as pointers.
+type StructData struct {
+	Struct      ds.Struct
+	Public      bool
+	UsePointers bool
+}
+
+// ConstructorData is passed to constructor.tmpl.
+// ClientName is the name of grafik client interface.
+type ConstructorData struct {
+	ClientName string
+}
+
+// InterfaceImplData is passed to interface_impl.tmpl.
+// ClientName is the name of grafik client interface and Func is implemented method executing the operation.
+type InterfaceImplData struct {
+	ClientName string
+	Func       ds.Func
+}
+
+// TypedInterfaceImplData is passed to interface_typed_impl.tmpl.
+// ResponseName is the name of the struct the response of the operation is decoded into.
+type TypedInterfaceImplData struct {
+	ClientName   string
+	Func         ds.Func
+	ResponseName string
+}
+
+// SubscriptionImplData is passed to subscription_impl.tmpl.
+// EventName is the name of the struct each event of the subscription is decoded into.
+type SubscriptionImplData struct {
+	ClientName string
+	Func       ds.Func
+	EventName  string
+}
+
+// InputMarshallerData is passed to input_marshal.tmpl.
+// OptionalFields are nullable fields of the input omitted when unset. NullFieldsName is the name of the field listing fields sent as explicit null.
+type InputMarshallerData struct {
+	Struct         ds.Struct
+	OptionalFields []ds.TypeField
+	NullFieldsName string
+}
+
+// EmbeddedUnmarshallerData is passed to embedded_unmarshal.tmpl.
+// Paths are selectors of all structs of named fragments embedded in the struct, including fragments embedded in other fragments - i.e. RocketInfo.RocketIds.
+type EmbeddedUnmarshallerData struct {
+	Struct ds.Struct
+	Paths  []string
+}
+
+// EmbeddedMarshallerData is passed to embedded_marshal.tmpl.
+// Paths are selectors of all structs of named fragments embedded in the struct, like in EmbeddedUnmarshallerData.
+type EmbeddedMarshallerData struct {
+	Struct ds.Struct
+	Paths  []string
+}
+
+// UnionData is passed to union.tmpl.
+// Union is Go interface of GraphQL interface or union with concrete structs of its possible types - the structs are written separately with struct.tmpl.
+type UnionData struct {
+	Union ds.Union
+}
+
+// GraphqlErrorData is passed to graphql_error.tmpl.
+// GraphQLErrorStructName is the name of generated GraphQL error struct.
+type GraphqlErrorData struct {
+	UsePointers            bool
+	NullablePointers       bool
+	GraphQLErrorStructName string
+}
@@ -5,11 +5,11 @@ go 1.17
 require (
 	github.com/stretchr/testify v1.7.0
 	github.com/vektah/gqlparser v1.3.1
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
 
 require (
 	github.com/agnivade/levenshtein v1.0.1 // indirect
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
@@ -2,40 +2,79 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"github.com/Bartosz-D3V/grafik/evaluator"
-	"github.com/vektah/gqlparser"
+	"github.com/Bartosz-D3V/grafik/codegen"
 	"github.com/vektah/gqlparser/ast"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"time"
 )
 
 // cli struct is a wrapper containing all fields required to generate grafik client provided through CLI arguments.
 type cli struct {
 	schemaSource *string
+	schemaHeader schemaHeaders
 	querySource  *string
 	packageName  *string
 	clientName   *string
 	destination  *string
 	usePointers  *bool
+	nullablePtrs *bool
+	perOpTypes   *bool
+	optInputs    *bool
+	discUnions   *bool
+	fragStructs  *bool
+	persisted    *bool
+	manifest     *bool
+	scalars      scalarBindings
+	templateDir  *string
+	fieldTags    fieldTags
 }
 
 func main() {
 	genCmd := flag.NewFlagSet("", flag.ExitOnError)
-	genSchemaSrc := genCmd.String("schema_source", "", "[required] Location of the GraphQL schema file. Either absolute or relative.")
-	genQuerySrc := genCmd.String("query_source", "", "[required] Location of the GraphQL query file. Either absolute or relative.")
+	genSchemaSrc := genCmd.String("schema_source", "", "[required] Location of the GraphQL schema - SDL file, JSON introspection result file or http(s) GraphQL endpoint queried via introspection. File location is either absolute or relative. Accepts comma separated list of locations and glob patterns (i.e. ./graphql/**/*.graphql).")
+	genSchemaHeader := make(schemaHeaders)
+	genCmd.Var(genSchemaHeader, "schema_header", "[optional] HTTP header sent with introspection query when schema_source is GraphQL endpoint in the form of Name: Value (i.e. Authorization: Bearer token); can be repeated.")
+	genQuerySrc := genCmd.String("query_source", "", "[required] Location of the GraphQL query file, Go source file or Go package directory with embedded operations. Either absolute or relative. Accepts comma separated list of locations and glob patterns (i.e. ./graphql/**/*.graphql).")
 	genPackageName := genCmd.String("package_name", "", "[optional] Name of the generated Go GraphQL client package; defaults to the name of the GraphQL query file with 'grafik_' prefix.")
 	genClientName := genCmd.String("client_name", "", "[optional] Name of the generated Go GraphQL client; defaults to the name of the GraphQL query file with 'Grafik' prefix and 'Client' postfix.")
 	genDestination := genCmd.String("destination", "./", "[optional] Output filename with path. Either absolute or relative; defaults to the current directory and client name.")
 	genUsePointers := genCmd.Bool("use_pointers", false, "[optional] Generate public GraphQL structs' fields as pointers; defaults to false.")
+	genNullablePtrs := genCmd.Bool("nullable_pointers", false, "[optional] Generate only nullable GraphQL fields (and nullable list elements) as pointers; defaults to false. Cannot be used with use_pointers.")
+	genScalars := make(scalarBindings)
+	genCmd.Var(genScalars, "scalar_binding", "[optional] Bind custom GraphQL scalar to Go type in the form of Name=[import/path.]TypeName (i.e. DateTime=time.Time); can be repeated. Unbound scalars are generated as interface{}.")
+	genPerOpTypes := genCmd.Bool("per_operation_types", false, "[optional] Generate selection-exact response structs for each operation instead of structs shared between operations; defaults to false.")
+	genOptInputs := genCmd.Bool("optional_inputs", false, "[optional] Omit unset nullable fields of GraphQL input types from the request and allow sending explicit null via NullFields; defaults to false.")
+	genDiscUnions := genCmd.Bool("discriminated_unions", false, "[optional] Generate GraphQL interfaces and unions as Go interfaces with concrete struct per possible type decoded based on __typename; defaults to false.")
+	genFragStructs := genCmd.Bool("fragment_structs", false, "[optional] Generate struct of each named GraphQL fragment embedded in all structs spreading the fragment; defaults to false.")
+	genPersisted := genCmd.Bool("persisted_queries", false, "[optional] Precompute SHA-256 hashes of operations and execute them as automatic persisted queries when enabled in the client; defaults to false.")
+	genManifest := genCmd.Bool("persisted_manifest", false, "[optional] Generate persisted operations manifest (Apollo persisted query manifest format) next to the generated client; requires persisted_queries flag; defaults to false.")
+	genTemplateDir := genCmd.String("template_dir", "", "[optional] Location of the directory with custom templates overriding embedded templates of the same name (i.e. struct.tmpl, interface.tmpl, interface_impl.tmpl, constructor.tmpl); defaults to embedded templates.")
+	genFieldTags := make(fieldTags, 0)
+	genCmd.Var(&genFieldTags, "field_tag", "[optional] Add struct tag of given key with the JSON name of the field as value to all fields of generated response and input structs (i.e. db); can be repeated.")
+	genConfig := genCmd.String("config", "", "[optional] Location of grafik config file describing multiple clients generated in one run; defaults to grafik.yaml in the current directory when schema_source and query_source are not provided.")
+	genPollInterval := genCmd.Duration("poll_interval", time.Second, "[optional] Interval of polling schema and query sources for changes in watch mode; defaults to 1s.")
 
-	if os.Args[1] == "help" {
+	if len(os.Args) > 1 && os.Args[1] == "help" {
 		usage(genCmd)
 		os.Exit(0)
 	}
 
-	err := genCmd.Parse(os.Args[1:])
+	args := os.Args[1:]
+	check := len(args) > 0 && args[0] == checkCmd
+	watch := len(args) > 0 && args[0] == watchCmd
+	if check || watch {
+		args = args[1:]
+	}
+
+	err := genCmd.Parse(args)
 	if err != nil {
 		usage(genCmd)
 		log.Fatalf("Failed to parse CLI arguments. Cause: %v", err)
@@ -46,65 +85,190 @@ func main() {
 		os.Exit(1)
 	}
 
-	cli := cli{
-		schemaSource: genSchemaSrc,
-		querySource:  genQuerySrc,
-		packageName:  genPackageName,
-		clientName:   genClientName,
-		destination:  genDestination,
-		usePointers:  genUsePointers,
+	var clis []cli
+	if *genConfig != "" || (*genSchemaSrc == "" && *genQuerySrc == "" && fileExists(defaultConfigFileName)) {
+		if *genSchemaSrc != "" || *genQuerySrc != "" {
+			usage(genCmd)
+			log.Fatal("config flag cannot be used with schema_source and query_source flags.")
+		}
+		configFileName := *genConfig
+		if configFileName == "" {
+			configFileName = defaultConfigFileName
+		}
+		clis, err = loadConfig(configFileName)
+		if err != nil {
+			log.Fatalf("Failed to load grafik config file %s. Cause: %v", configFileName, err)
+		}
+	} else {
+		c := cli{
+			schemaSource: genSchemaSrc,
+			schemaHeader: genSchemaHeader,
+			querySource:  genQuerySrc,
+			packageName:  genPackageName,
+			clientName:   genClientName,
+			destination:  genDestination,
+			usePointers:  genUsePointers,
+			nullablePtrs: genNullablePtrs,
+			perOpTypes:   genPerOpTypes,
+			optInputs:    genOptInputs,
+			discUnions:   genDiscUnions,
+			fragStructs:  genFragStructs,
+			persisted:    genPersisted,
+			manifest:     genManifest,
+			scalars:      genScalars,
+			templateDir:  genTemplateDir,
+			fieldTags:    genFieldTags,
+		}
+
+		if err := c.validate(); err != nil {
+			usage(genCmd)
+			log.Fatal(err)
+		}
+		clis = []cli{c}
 	}
 
-	if *cli.schemaSource == "" || *cli.querySource == "" {
-		usage(genCmd)
-		log.Fatal("grafikgen requires at least two flags - schema_source and query_source.")
+	if watch {
+		watchClients(clis, *genPollInterval, log.New(os.Stdout, "", log.LstdFlags), nil)
+		return
 	}
 
-	defer func() {
-		if r := recover(); r != nil {
-			log.Fatalf("Failed to generate grafik client. Cause: %v", r)
+	if check {
+		upToDate := true
+		for _, c := range clis {
+			if !c.check(os.Stdout) {
+				upToDate = false
+			}
+		}
+		if !upToDate {
+			log.Fatal("Generated grafik client is stale - regenerate it with grafikgen.")
 		}
-	}()
+		return
+	}
 
-	schemaContent, err := getFileContent(cli.schemaSource)
-	if err != nil {
-		panic(fmt.Errorf("failed to read content of GraphQL schema file. Cause: %s", err.Error()))
+	for _, c := range clis {
+		c.generate()
 	}
+}
 
-	schema, err := gqlparser.LoadSchema(&ast.Source{
-		Input: string(schemaContent),
-	})
+// validate checks if the combination of provided CLI arguments is correct.
+func (c cli) validate() error {
+	if *c.schemaSource == "" || *c.querySource == "" {
+		return errors.New("grafikgen requires at least two flags - schema_source and query_source.")
+	}
+
+	if *c.usePointers && *c.nullablePtrs {
+		return errors.New("use_pointers and nullable_pointers flags are mutually exclusive.")
+	}
+
+	if *c.manifest && !*c.persisted {
+		return errors.New("persisted_manifest flag requires persisted_queries flag.")
+	}
+	return nil
+}
 
-	// gqlparser returns err that is not nil even when schema is parsed correctly.
-	if err.Error() != "" {
-		panic(fmt.Errorf("failed to parse GraphQL schema file. Cause: %s", err.Error()))
+// generate generates grafik client (and persisted operations manifest if requested) based on CLI arguments.
+func (c cli) generate() {
+	if err := c.write(); err != nil {
+		log.Fatalf("Failed to generate grafik client. Cause: %v", err)
 	}
+}
 
-	queryContent, err := getFileContent(cli.querySource)
+// write generates grafik client (and persisted operations manifest if requested) and writes it to the destination.
+func (c cli) write() error {
+	files, err := c.build()
 	if err != nil {
-		panic(fmt.Errorf("failed to read content of GraphQL query file. Cause: %s", err.Error()))
+		return err
 	}
+	for _, f := range files {
+		// Unchanged file is not rewritten, so its modification time is kept - i.e. it is not considered as changed source in watch mode.
+		if current, err := ioutil.ReadFile(f.name); err == nil && bytes.Equal(current, f.content) {
+			continue
+		}
+		err := writeFile(bytes.NewBuffer(f.content), f.name)
+		if err != nil {
+			return fmt.Errorf("failed to write content to the generated file. Cause: %w", err)
+		}
+	}
+	return nil
+}
 
-	query, err := gqlparser.LoadQuery(schema, string(queryContent))
-	// gqlparser returns err that is not nil even when schema is parsed correctly.
-	if err.Error() != "" {
-		panic(fmt.Errorf("failed to parse GraphQL query file. Cause: %s", err.Error()))
+// check generates grafik client into memory and compares it with the files at the destination.
+// Unified diff of each stale file is written to out. Returns false if any of the files is stale.
+func (c cli) check(out io.Writer) bool {
+	files, err := c.build()
+	if err != nil {
+		log.Fatalf("Failed to generate grafik client. Cause: %v", err)
 	}
 
-	additionalInfo := evaluator.AdditionalInfo{
-		PackageName: cli.parsePackageName(),
-		ClientName:  cli.parseClientName(),
-		UsePointers: *genUsePointers,
+	upToDate := true
+	for _, f := range files {
+		current, err := ioutil.ReadFile(f.name)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatalf("Failed to read content of the generated file. Cause: %v", err)
+		}
+		if bytes.Equal(current, f.content) {
+			continue
+		}
+		upToDate = false
+		_, _ = io.WriteString(out, unifiedDiff(f.name, string(current), string(f.content)))
 	}
+	return upToDate
+}
 
-	e := evaluator.New(schema, query, additionalInfo)
+// generatedFile is the content of the file generated by grafikgen together with its destination.
+type generatedFile struct {
+	name    string
+	content []byte
+}
 
-	fileName := cli.getFileDestName(additionalInfo.ClientName)
+// build generates grafik client (and persisted operations manifest if requested) into memory.
+// All problems found by the evaluator are returned together as evaluator.Diagnostics.
+func (c cli) build() ([]generatedFile, error) {
+	schemaSources, err := c.getSchemaSources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content of GraphQL schema. Cause: %w", err)
+	}
 
-	fileContent := e.Generate()
+	querySources, err := c.getQuerySources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content of GraphQL query file. Cause: %w", err)
+	}
 
-	err = writeFile(fileContent, fileName)
+	clientName := c.parseClientName()
+	files, err := codegen.Generate(context.Background(), codegen.Config{
+		Schema:              toCodegenSources(schemaSources),
+		Query:               toCodegenSources(querySources),
+		PackageName:         c.parsePackageName(),
+		ClientName:          clientName,
+		FileName:            c.getFileDestName(clientName),
+		UsePointers:         *c.usePointers,
+		NullablePointers:    *c.nullablePtrs,
+		PerOperationTypes:   *c.perOpTypes,
+		OptionalInputs:      *c.optInputs,
+		DiscriminatedUnions: *c.discUnions,
+		FragmentStructs:     *c.fragStructs,
+		PersistedQueries:    *c.persisted,
+		PersistedManifest:   *c.manifest,
+		ScalarBindings:      c.scalars,
+		TemplateDir:         c.getTemplateDir(),
+		Plugins:             c.plugins(),
+	})
 	if err != nil {
-		panic(fmt.Errorf("failed to write content to the generated file. Cause: %w", err))
+		return nil, err
+	}
+
+	generated := make([]generatedFile, len(files))
+	for i, f := range files {
+		generated[i] = generatedFile{name: f.Name, content: f.Content}
+	}
+	return generated, nil
+}
+
+// toCodegenSources maps GraphQL sources read by grafikgen to codegen sources.
+func toCodegenSources(sources []*ast.Source) []codegen.Source {
+	codegenSources := make([]codegen.Source, len(sources))
+	for i, src := range sources {
+		codegenSources[i] = codegen.Source{Name: src.Name, Input: src.Input}
 	}
+	return codegenSources
 }
@@ -1,7 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/introspection"
 	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/ast"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -114,6 +123,217 @@ func TestCli_getFileDestName(t *testing.T) {
 	}
 }
 
+func TestScalarBindings_Set(t *testing.T) {
+	t.Parallel()
+	bindings := make(scalarBindings)
+
+	assert.NoError(t, bindings.Set("DateTime=time.Time"))
+	assert.NoError(t, bindings.Set("UUID=github.com/google/uuid.UUID"))
+
+	assert.Equal(t, scalarBindings{
+		"DateTime": "time.Time",
+		"UUID":     "github.com/google/uuid.UUID",
+	}, bindings)
+	assert.Equal(t, "DateTime=time.Time,UUID=github.com/google/uuid.UUID", bindings.String())
+}
+
+func TestScalarBindings_Set_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		v      string
+		expErr string
+	}{
+		{"DateTime", "invalid scalar binding \"DateTime\" - expected Name=[import/path.]TypeName"},
+		{"=time.Time", "invalid scalar binding \"=time.Time\" - expected Name=[import/path.]TypeName"},
+		{"DateTime=time.", "invalid Go type \"time.\" - expected [import/path.]TypeName"},
+	}
+	for _, test := range tests {
+		assert.EqualError(t, make(scalarBindings).Set(test.v), test.expErr)
+	}
+}
+
+func TestSchemaHeaders_Set(t *testing.T) {
+	t.Parallel()
+	headers := make(schemaHeaders)
+
+	assert.NoError(t, headers.Set("Authorization: Bearer token"))
+	assert.NoError(t, headers.Set("X-Trace-Id:081cdde6"))
+
+	assert.Equal(t, schemaHeaders{
+		"Authorization": {"Bearer token"},
+		"X-Trace-Id":    {"081cdde6"},
+	}, headers)
+	assert.Equal(t, "Authorization: Bearer token,X-Trace-Id: 081cdde6", headers.String())
+}
+
+func TestSchemaHeaders_Set_Error(t *testing.T) {
+	t.Parallel()
+	for _, v := range []string{"Authorization", ": Bearer token"} {
+		assert.EqualError(t, make(schemaHeaders).Set(v), fmt.Sprintf("invalid schema header %q - expected Name: Value", v))
+	}
+}
+
+func TestCli_getSchemaSources(t *testing.T) {
+	t.Parallel()
+	b, err := ioutil.ReadFile("../test/introspection/schema.json")
+	assert.NoError(t, err)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, err := w.Write(b)
+		assert.NoError(t, err)
+	}))
+	defer svr.Close()
+
+	schema, err := introspection.Parse(b)
+	assert.NoError(t, err)
+	sdl, err := ioutil.ReadFile("../test/input/schema.graphql")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		cli cli
+		exp []*ast.Source
+	}{
+		{
+			cli{schemaSource: strPtr("../test/input/schema.graphql")},
+			[]*ast.Source{{Name: "../test/input/schema.graphql", Input: string(sdl)}},
+		},
+		{
+			cli{schemaSource: strPtr("../test/introspection/schema.json")},
+			[]*ast.Source{{Name: "../test/introspection/schema.json", Input: schema.SDL()}},
+		},
+		{
+			cli{schemaSource: strPtr(svr.URL), schemaHeader: schemaHeaders{"Authorization": {"Bearer token"}}},
+			[]*ast.Source{{Name: svr.URL, Input: schema.SDL()}},
+		},
+		{
+			cli{schemaSource: strPtr("../test/input/schema.graphql, ../test/introspection/schema.json")},
+			[]*ast.Source{
+				{Name: "../test/input/schema.graphql", Input: string(sdl)},
+				{Name: "../test/introspection/schema.json", Input: schema.SDL()},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		sources, err := test.cli.getSchemaSources()
+		assert.NoError(t, err)
+		assert.Equal(t, test.exp, sources)
+	}
+}
+
+func TestExpandSources(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src string
+		exp []string
+	}{
+		{
+			"../test/multi_file/query/rockets.graphql",
+			[]string{"../test/multi_file/query/rockets.graphql"},
+		},
+		{
+			"../test/multi_file/query/*.graphql",
+			[]string{"../test/multi_file/query/launches.graphql", "../test/multi_file/query/rockets.graphql"},
+		},
+		{
+			"../test/multi_file/query/**/*.graphql",
+			[]string{
+				"../test/multi_file/query/fragments/rocket_info.graphql",
+				"../test/multi_file/query/launches.graphql",
+				"../test/multi_file/query/rockets.graphql",
+			},
+		},
+		{
+			"../test/multi_file/query/rockets.graphql,../test/multi_file/query/*.graphql",
+			[]string{"../test/multi_file/query/rockets.graphql", "../test/multi_file/query/launches.graphql"},
+		},
+		{
+			"../test/multi_file/**/rocket*.graphql",
+			[]string{"../test/multi_file/query/fragments/rocket_info.graphql", "../test/multi_file/query/rockets.graphql", "../test/multi_file/schema/types/rocket.graphql"},
+		},
+	}
+
+	for _, test := range tests {
+		sources, err := expandSources(test.src)
+		assert.NoError(t, err)
+		assert.Equal(t, test.exp, sources)
+	}
+}
+
+func TestExpandSources_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src    string
+		expErr string
+	}{
+		{"", "provided source path is empty"},
+		{" , ", "provided source path is empty"},
+		{"../test/multi_file/**/*.json", "pattern ../test/multi_file/**/*.json does not match any file"},
+	}
+
+	for _, test := range tests {
+		_, err := expandSources(test.src)
+		assert.EqualError(t, err, test.expErr)
+	}
+}
+
+func TestCli_queryFileName_Glob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cli cli
+		exp string
+	}{
+		{cli{querySource: strPtr("./graphql/**/*.graphql")}, "graphql"},
+		{cli{querySource: strPtr("/app/space_x/*.graphql")}, "space_x"},
+		{cli{querySource: strPtr("rockets.graphql,launches.graphql")}, "rockets"},
+		{cli{querySource: strPtr("../test/go_source")}, "go_source"},
+		{cli{querySource: strPtr(".")}, "grafikgen"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.exp, test.cli.queryFileName())
+	}
+}
+
+func TestCli_check(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	c := cli{
+		schemaSource: strPtr("../test/multi_file/schema/**/*.graphql"),
+		querySource:  strPtr("../test/multi_file/query/**/*.graphql"),
+		packageName:  strPtr("space_x"),
+		clientName:   strPtr("SpaceXClient"),
+		destination:  strPtr(filepath.Join(dir, "client.go")),
+		usePointers:  boolPtr(false),
+		nullablePtrs: boolPtr(false),
+		perOpTypes:   boolPtr(false),
+		optInputs:    boolPtr(false),
+		discUnions:   boolPtr(false),
+		fragStructs:  boolPtr(false),
+		persisted:    boolPtr(true),
+		manifest:     boolPtr(true),
+	}
+
+	var out bytes.Buffer
+	assert.False(t, c.check(&out))
+	assert.Contains(t, out.String(), fmt.Sprintf("--- %s\n+++ %s (generated)\n@@ -0,0 +1,", *c.destination, *c.destination))
+	assert.Contains(t, out.String(), filepath.Join(dir, "persisted-query-manifest.json"))
+
+	c.generate()
+	out.Reset()
+	assert.True(t, c.check(&out))
+	assert.Empty(t, out.String())
+
+	content, err := ioutil.ReadFile(*c.destination)
+	assert.NoError(t, err)
+	stale := strings.Replace(string(content), "package space_x\n", "package space_y\n", 1)
+	assert.NoError(t, ioutil.WriteFile(*c.destination, []byte(stale), 0600))
+
+	assert.False(t, c.check(&out))
+	assert.Contains(t, out.String(), "\n-package space_y\n+package space_x\n")
+	assert.NotContains(t, out.String(), "persisted-query-manifest.json")
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,159 @@
+// Package main provides grafikgen CLI tools used for generating grafik clients.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultConfigFileName is the name of grafik config file used when neither config flag nor schema & query sources are provided.
+const defaultConfigFileName = "grafik.yaml"
+
+// config represents grafik config file describing multiple grafik clients generated in one run.
+type config struct {
+	Targets []target `yaml:"targets"`
+}
+
+// target represents single grafik client of the config file. Fields correspond to grafikgen CLI flags.
+// Relative locations are resolved against the directory of the config file.
+type target struct {
+	SchemaSource      sources           `yaml:"schema_source"`
+	SchemaHeaders     map[string]string `yaml:"schema_headers"`
+	QuerySource       sources           `yaml:"query_source"`
+	PackageName       string            `yaml:"package_name"`
+	ClientName        string            `yaml:"client_name"`
+	Destination       string            `yaml:"destination"`
+	UsePointers       bool              `yaml:"use_pointers"`
+	NullablePointers  bool              `yaml:"nullable_pointers"`
+	PerOperationTypes bool              `yaml:"per_operation_types"`
+	OptionalInputs    bool              `yaml:"optional_inputs"`
+	DiscUnions        bool              `yaml:"discriminated_unions"`
+	FragmentStructs   bool              `yaml:"fragment_structs"`
+	PersistedQueries  bool              `yaml:"persisted_queries"`
+	PersistedManifest bool              `yaml:"persisted_manifest"`
+	ScalarBindings    map[string]string `yaml:"scalar_bindings"`
+	TemplateDir       string            `yaml:"template_dir"`
+	FieldTags         []string          `yaml:"field_tags"`
+}
+
+// sources is a list of schema or query locations - either single location or a list of locations.
+type sources []string
+
+// UnmarshalYAML decodes either single location or a list of locations.
+func (s *sources) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*s = sources{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// loadConfig reads grafik config file and maps each of its targets to cli.
+func loadConfig(fileName string) ([]cli, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var c config
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+	if len(c.Targets) == 0 {
+		return nil, errors.New("config file does not define any targets")
+	}
+
+	dir := filepath.Dir(fileName)
+	clis := make([]cli, len(c.Targets))
+	for i, t := range c.Targets {
+		cli, err := t.cli(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target #%d. Cause: %w", i+1, err)
+		}
+		if err := cli.validate(); err != nil {
+			return nil, fmt.Errorf("invalid target #%d. Cause: %w", i+1, err)
+		}
+		clis[i] = cli
+	}
+	return clis, nil
+}
+
+// cli maps config file target to cli. Relative locations are resolved against dir.
+func (t target) cli(dir string) (cli, error) {
+	scalars := make(scalarBindings)
+	for name, goType := range t.ScalarBindings {
+		if err := scalars.Set(fmt.Sprintf("%s=%s", name, goType)); err != nil {
+			return cli{}, err
+		}
+	}
+	headers := make(schemaHeaders)
+	for name, value := range t.SchemaHeaders {
+		if err := headers.Set(fmt.Sprintf("%s: %s", name, value)); err != nil {
+			return cli{}, err
+		}
+	}
+
+	destination := "./"
+	if t.Destination != "" {
+		destination = t.Destination
+	}
+	schemaSource := t.SchemaSource.resolve(dir)
+	querySource := t.QuerySource.resolve(dir)
+	destination = resolvePath(dir, destination)
+	templateDir := resolvePath(dir, t.TemplateDir)
+
+	return cli{
+		schemaSource: &schemaSource,
+		schemaHeader: headers,
+		querySource:  &querySource,
+		packageName:  &t.PackageName,
+		clientName:   &t.ClientName,
+		destination:  &destination,
+		usePointers:  &t.UsePointers,
+		nullablePtrs: &t.NullablePointers,
+		perOpTypes:   &t.PerOperationTypes,
+		optInputs:    &t.OptionalInputs,
+		discUnions:   &t.DiscUnions,
+		fragStructs:  &t.FragmentStructs,
+		persisted:    &t.PersistedQueries,
+		manifest:     &t.PersistedManifest,
+		scalars:      scalars,
+		templateDir:  &templateDir,
+		fieldTags:    t.FieldTags,
+	}, nil
+}
+
+// resolve returns comma separated list of locations resolved against dir.
+func (s sources) resolve(dir string) string {
+	locations := make([]string, len(s))
+	for i, src := range s {
+		locations[i] = resolvePath(dir, src)
+	}
+	return strings.Join(locations, ",")
+}
+
+// resolvePath returns location relative to dir. Absolute paths and URLs are returned unchanged.
+func resolvePath(dir string, src string) string {
+	if src == "" || filepath.IsAbs(src) || strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return src
+	}
+	return filepath.Join(dir, src)
+}
+
+// fileExists checks if the file exists.
+func fileExists(fileName string) bool {
+	info, err := os.Stat(fileName)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	clis, err := loadConfig("../test/config/grafik.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []cli{
+		{
+			schemaSource: strPtr("../test/multi_file/schema/**/*.graphql"),
+			schemaHeader: schemaHeaders{},
+			querySource:  strPtr("../test/multi_file/query/rockets.graphql,../test/multi_file/query/fragments/*.graphql"),
+			packageName:  strPtr("space_x"),
+			clientName:   strPtr("SpaceXClient"),
+			destination:  strPtr("../test/config/space_x/client.go"),
+			usePointers:  boolPtr(false),
+			nullablePtrs: boolPtr(true),
+			perOpTypes:   boolPtr(false),
+			optInputs:    boolPtr(false),
+			discUnions:   boolPtr(false),
+			fragStructs:  boolPtr(false),
+			persisted:    boolPtr(true),
+			manifest:     boolPtr(true),
+			scalars:      scalarBindings{},
+			templateDir:  strPtr(""),
+			fieldTags:    fieldTags{"db"},
+		},
+		{
+			schemaSource: strPtr("https://api.example.com/graphql"),
+			schemaHeader: schemaHeaders{"Authorization": {"Bearer token"}},
+			querySource:  strPtr("../test/scalar_binding/query.graphql"),
+			packageName:  strPtr(""),
+			clientName:   strPtr(""),
+			destination:  strPtr("../test/config"),
+			usePointers:  boolPtr(true),
+			nullablePtrs: boolPtr(false),
+			perOpTypes:   boolPtr(false),
+			optInputs:    boolPtr(false),
+			discUnions:   boolPtr(false),
+			fragStructs:  boolPtr(false),
+			persisted:    boolPtr(false),
+			manifest:     boolPtr(false),
+			scalars:      scalarBindings{"DateTime": "time.Time"},
+			templateDir:  strPtr("../test/config/templates"),
+		},
+	}, clis)
+}
+
+func TestLoadConfig_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		content string
+		expErr  string
+	}{
+		{
+			"targets: []",
+			"config file does not define any targets",
+		},
+		{
+			"targets:\n  - schema_source: schema.graphql",
+			"invalid target #1. Cause: grafikgen requires at least two flags - schema_source and query_source.",
+		},
+		{
+			"targets:\n  - schema_source: schema.graphql\n    query_source: query.graphql\n    use_pointers: true\n    nullable_pointers: true",
+			"invalid target #1. Cause: use_pointers and nullable_pointers flags are mutually exclusive.",
+		},
+		{
+			"targets:\n  - schema_source: schema.graphql\n    query_source: query.graphql\n    persisted_manifest: true",
+			"invalid target #1. Cause: persisted_manifest flag requires persisted_queries flag.",
+		},
+		{
+			"targets:\n  - schema_source: schema.graphql\n    query_source: query.graphql\n    scalar_bindings:\n      DateTime: time.",
+			"invalid target #1. Cause: invalid Go type \"time.\" - expected [import/path.]TypeName",
+		},
+		{
+			"targets:\n  - schema_src: schema.graphql",
+			"yaml: unmarshal errors:\n  line 2: field schema_src not found in type main.target",
+		},
+	}
+
+	dir := t.TempDir()
+	for i, test := range tests {
+		fileName := filepath.Join(dir, fmt.Sprintf("grafik_%d.yaml", i))
+		assert.NoError(t, ioutil.WriteFile(fileName, []byte(test.content), 0600))
+
+		_, err := loadConfig(fileName)
+		assert.EqualError(t, err, test.expErr)
+	}
+}
+
+func TestLoadConfig_Generate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	config := `targets:
+  - schema_source: ` + filepath.Join(wd(t), "../test/multi_file/schema/**/*.graphql") + `
+    query_source: ` + filepath.Join(wd(t), "../test/multi_file/query/**/*.graphql") + `
+    package_name: space_x
+    destination: ./space_x/
+    persisted_queries: true
+    persisted_manifest: true
+  - schema_source: ` + filepath.Join(wd(t), "../test/fragment/schema.graphql") + `
+    query_source: ` + filepath.Join(wd(t), "../test/fragment/query.graphql") + `
+    client_name: RocketClient
+    destination: ./rocket/rocket.go
+`
+	fileName := filepath.Join(dir, defaultConfigFileName)
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(config), 0600))
+
+	clis, err := loadConfig(fileName)
+	assert.NoError(t, err)
+	for _, cli := range clis {
+		cli.generate()
+	}
+
+	for _, f := range []string{"space_x/GrafikQueryClient.go", "space_x/persisted-query-manifest.json", "rocket/rocket.go"} {
+		assert.True(t, fileExists(filepath.Join(dir, f)), f)
+	}
+}
+
+func wd(t *testing.T) string {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	return wd
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,160 @@
+// Package main provides grafikgen CLI tools used for generating grafik clients.
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines displayed around each change of unified diff.
+const diffContext = 3
+
+// maxDiffCells limits the size of the table of the longest common subsequences (64 MiB).
+// Files with larger changed part are reported as different without the diff.
+const maxDiffCells = 1 << 24
+
+// diffOp is single line of the edit script - either unchanged (' '), removed ('-') or added ('+').
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns unified diff between current content of the file and its generated content.
+// If the changed part of the file is too large to be compared, only the note that the files differ is returned.
+func unifiedDiff(fileName string, current string, generated string) string {
+	ops, ok := diffLines(splitLines(current), splitLines(generated))
+	if !ok {
+		return fmt.Sprintf("Files %s and %s (generated) differ\n", fileName, fileName)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s (generated)\n", fileName, fileName)
+
+	// Line numbers (0-based) of the current and generated content at each op.
+	curLine, genLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		curLine[i+1], genLine[i+1] = curLine[i], genLine[i]
+		if op.kind != '+' {
+			curLine[i+1]++
+		}
+		if op.kind != '-' {
+			genLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk until there are more than two contexts of unchanged lines between the changes.
+		end, unchanged := i, 0
+		for j := i; j < len(ops) && unchanged <= 2*diffContext; j++ {
+			if ops[j].kind == ' ' {
+				unchanged++
+				continue
+			}
+			unchanged = 0
+			end = j + 1
+		}
+		end += diffContext
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(curLine[start], curLine[end]), hunkRange(genLine[start], genLine[end]))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end - 1
+	}
+	return sb.String()
+}
+
+// hunkRange returns range of the lines of unified diff hunk - empty range starts at the preceding line.
+func hunkRange(start int, end int) string {
+	count := end - start
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits content into lines keeping line breaks.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns edit script transforming a into b based on the longest common subsequence of the lines.
+// Common prefix and suffix are skipped, so only the changed part of the file is compared.
+// Returns false if the changed part requires more than maxDiffCells cells of the table.
+func diffLines(a []string, b []string) ([]diffOp, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if int64(len(midA)+1)*int64(len(midB)+1) > maxDiffCells {
+		return nil, false
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:].
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			switch {
+			case midA[i] == midB[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{' ', midA[i]})
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', midA[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', midB[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops, true
+}
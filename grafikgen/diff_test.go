@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		current   string
+		generated string
+		exp       string
+	}{
+		{
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- client.go\n+++ client.go (generated)\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"",
+			"a\nb\n",
+			"--- client.go\n+++ client.go (generated)\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			"a\nb\n",
+			"a\nb\nc",
+			"--- client.go\n+++ client.go (generated)\n@@ -1,2 +1,3 @@\n a\n b\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			lines(1, 20),
+			strings.Replace(strings.Replace(lines(1, 20), "2\n", "two\n", 1), "18\n", "", 1),
+			"--- client.go\n+++ client.go (generated)\n" +
+				"@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+				"@@ -15,6 +15,5 @@\n 15\n 16\n 17\n-18\n 19\n 20\n",
+		},
+		{
+			lines(1, 10),
+			strings.Replace(strings.Replace(lines(1, 10), "2\n", "two\n", 1), "8\n", "", 1),
+			"--- client.go\n+++ client.go (generated)\n" +
+				"@@ -1,10 +1,9 @@\n 1\n-2\n+two\n 3\n 4\n 5\n 6\n 7\n-8\n 9\n 10\n",
+		},
+		{
+			"header\n" + lines(1, 5000) + "footer\n",
+			"header\n" + lines(5001, 10000) + "footer\n",
+			"Files client.go and client.go (generated) differ\n",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.exp, unifiedDiff("client.go", test.current, test.generated))
+	}
+}
+
+func lines(from int, to int) string {
+	var sb strings.Builder
+	for i := from; i <= to; i++ {
+		_, _ = fmt.Fprintf(&sb, "%d\n", i)
+	}
+	return sb.String()
+}
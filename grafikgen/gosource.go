@@ -0,0 +1,140 @@
+// Package main provides grafikgen CLI tools used for generating grafik clients.
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// graphQLMarker marks Go string literal containing GraphQL operation - i.e. const q = /* GraphQL */ `query {...}`.
+	graphQLMarker = "/* GraphQL */"
+	// queryDirective marks comment block containing GraphQL operation in the following lines of the comment.
+	queryDirective = "//grafik:query"
+)
+
+// embeddedOperation is GraphQL code embedded in Go source file together with the line it starts at.
+type embeddedOperation struct {
+	line int
+	text string
+}
+
+// isGoSource checks if the query source is Go source file.
+func isGoSource(src string) bool {
+	return strings.EqualFold(filepath.Ext(src), ".go")
+}
+
+// getGoPackageFiles returns all non-test Go source files of the package in the directory.
+func getGoPackageFiles(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	goFiles := make([]string, 0)
+	for _, f := range files {
+		if f.IsDir() || !isGoSource(f.Name()) || strings.HasSuffix(f.Name(), "_test.go") {
+			continue
+		}
+		goFiles = append(goFiles, filepath.Join(dir, f.Name()))
+	}
+	return goFiles, nil
+}
+
+// extractOperations returns GraphQL code embedded in Go source file - either as string literal marked with /* GraphQL */ comment or as //grafik:query comment block.
+// Embedded code is placed at the same lines as in Go source file, so GraphQL errors point to the right line. Empty string is returned if the file does not embed any GraphQL code.
+func extractOperations(fileName string) (string, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, fileName, content, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	ops := make([]embeddedOperation, 0)
+	for _, group := range f.Comments {
+		if op, ok := extractDirectiveOperation(fset, group); ok {
+			ops = append(ops, op)
+		}
+	}
+	var litErr error
+	ast.Inspect(f, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING || !isMarked(fset, content, f.Comments, lit) {
+			return true
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			litErr = fmt.Errorf("failed to read GraphQL string at %s. Cause: %w", fset.Position(lit.Pos()), err)
+			return false
+		}
+		ops = append(ops, embeddedOperation{line: fset.Position(lit.Pos()).Line, text: text})
+		return true
+	})
+	if litErr != nil {
+		return "", litErr
+	}
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].line < ops[j].line
+	})
+	var sb strings.Builder
+	line := 1
+	for _, op := range ops {
+		for ; line < op.line; line++ {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(op.text)
+		sb.WriteString("\n")
+		line += strings.Count(op.text, "\n") + 1
+	}
+	return sb.String(), nil
+}
+
+// extractDirectiveOperation returns GraphQL code of the comment block starting with //grafik:query directive.
+func extractDirectiveOperation(fset *token.FileSet, group *ast.CommentGroup) (embeddedOperation, bool) {
+	for i, c := range group.List {
+		if strings.TrimSpace(c.Text) != queryDirective {
+			continue
+		}
+		lines := make([]string, 0, len(group.List)-i-1)
+		for _, l := range group.List[i+1:] {
+			text := strings.TrimPrefix(l.Text, "//")
+			lines = append(lines, strings.TrimPrefix(text, " "))
+		}
+		if len(lines) == 0 {
+			return embeddedOperation{}, false
+		}
+		return embeddedOperation{
+			line: fset.Position(group.List[i+1].Pos()).Line,
+			text: strings.Join(lines, "\n"),
+		}, true
+	}
+	return embeddedOperation{}, false
+}
+
+// isMarked checks if the string literal is preceded by /* GraphQL */ comment.
+func isMarked(fset *token.FileSet, content []byte, comments []*ast.CommentGroup, lit *ast.BasicLit) bool {
+	litOffset := fset.Position(lit.Pos()).Offset
+	for _, group := range comments {
+		for _, c := range group.List {
+			if !strings.EqualFold(c.Text, graphQLMarker) {
+				continue
+			}
+			end := fset.Position(c.End()).Offset
+			if end <= litOffset && strings.TrimSpace(string(content[end:litOffset])) == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
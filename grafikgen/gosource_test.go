@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/Bartosz-D3V/grafik/codegen"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractOperations(t *testing.T) {
+	t.Parallel()
+	ops, err := extractOperations("../test/go_source/rockets.go")
+	assert.NoError(t, err)
+
+	lines := strings.Split(ops, "\n")
+	assert.Equal(t, "query getRockets($limit: Int) {", lines[5])
+	assert.Equal(t, "}", lines[9])
+	assert.Equal(t, "fragment RocketInfo on Rocket {", lines[12])
+	assert.Equal(t, "    id", lines[13])
+	assert.NotContains(t, ops, "notGraphQL")
+
+	ops, err = extractOperations("../test/go_source/launches.go")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("\n", 4)+"query getLaunches { launches { mission_name rocket { ...RocketInfo } } }\n", ops)
+}
+
+func TestExtractOperations_NoOperations(t *testing.T) {
+	t.Parallel()
+	ops, err := extractOperations("gosource.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ops)
+}
+
+func TestCli_getQuerySources_GoPackage(t *testing.T) {
+	t.Parallel()
+	schemaSources, err := cli{schemaSource: strPtr("../test/multi_file/schema/**/*.graphql")}.getSchemaSources()
+	assert.NoError(t, err)
+	schema, gqlErr := gqlparser.LoadSchema(schemaSources...)
+	assert.Nil(t, gqlErr)
+
+	for _, src := range []string{"../test/go_source", "../test/go_source/*.go"} {
+		querySources, err := cli{querySource: strPtr(src)}.getQuerySources()
+		assert.NoError(t, err)
+		assert.Len(t, querySources, 2)
+
+		query, errs := codegen.LoadQuery(schema, querySources)
+		assert.Nil(t, errs)
+		assert.Equal(t, "getLaunches", query.Operations[0].Name)
+		assert.Equal(t, "getRockets", query.Operations[1].Name)
+		assert.Equal(t, "RocketInfo", query.Fragments[0].Name)
+		assert.Equal(t, 6, query.Operations[1].Position.Line)
+	}
+}
+
+func TestCli_getQuerySources_GoPackage_Error(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+
+	_, err := cli{querySource: strPtr(dir)}.getQuerySources()
+	assert.EqualError(t, err, "no GraphQL operations found in Go package "+dir)
+}
+
+func TestLoadQuery_GoSource_Error(t *testing.T) {
+	t.Parallel()
+	schemaSources, err := cli{schemaSource: strPtr("../test/multi_file/schema/**/*.graphql")}.getSchemaSources()
+	assert.NoError(t, err)
+	schema, gqlErr := gqlparser.LoadSchema(schemaSources...)
+	assert.Nil(t, gqlErr)
+
+	fileName := filepath.Join(t.TempDir(), "rockets.go")
+	src := "package rockets\n\nconst getRockets = /* GraphQL */ `\nquery getRockets {\n    rockets {\n        launched\n    }\n}`\n"
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(src), 0600))
+
+	querySources, err := cli{querySource: strPtr(fileName)}.getQuerySources()
+	assert.NoError(t, err)
+	_, errs := codegen.LoadQuery(schema, querySources)
+	assert.EqualError(t, errs, fileName+":6: Cannot query field \"launched\" on type \"Rocket\".\n")
+}
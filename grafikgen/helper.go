@@ -2,16 +2,24 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"github.com/Bartosz-D3V/grafik/common"
+	"github.com/Bartosz-D3V/grafik/evaluator"
+	"github.com/Bartosz-D3V/grafik/introspection"
+	"github.com/Bartosz-D3V/grafik/plugin"
+	"github.com/vektah/gqlparser/ast"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 const usageTxt = `grafik is GraphQL schema based client and generator.
@@ -20,6 +28,8 @@ grafikgen is a CLI for generating GraphQL Go clients.
 
 Supported sub-commands:
 	help - prints this message
+	watch - regenerates grafik client whenever schema or query sources change
+	check - generates grafik client into memory and compares it with the file at destination; exits with non-zero code and prints unified diff if the file is stale
 
 Generate Go GraphQL client by providing location of GraphQL schema and GraphQL queries file.
 Example:
@@ -29,6 +39,65 @@ To customize generated Go GraphQL client provide auxiliary options for client na
 Example:
 	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -package=app -client_name=MyGraphqlClient -destination=./app/my_client.go
 
+Schema and query sources accept comma separated list of files and glob patterns ('**' matches any number of directories).
+Example:
+	grafikgen -schema_source="./graphql/schema/**/*.graphql" -query_source="./graphql/operations/*.graphql,./app/**/*.graphql"
+
+Operations embedded in Go source files are extracted when Go file or Go package directory is provided as query_source.
+GraphQL string literals marked with /* GraphQL */ comment and //grafik:query comment blocks are extracted.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./app/rockets
+
+To load GraphQL schema from live endpoint via introspection provide URL as schema_source and optional schema_header options.
+JSON introspection result file (i.e. schema.json) is also accepted as schema_source.
+Example:
+	grafikgen -schema_source=https://api.example.com/graphql -schema_header="Authorization: Bearer token" -query_source=./schemas/my_query.graphql
+
+To bind custom GraphQL scalars to Go types provide scalar_binding option for each scalar.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -scalar_binding=DateTime=time.Time -scalar_binding=UUID=github.com/google/uuid.UUID
+
+To omit unset nullable fields of GraphQL input types from the request provide optional_inputs option.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -optional_inputs
+
+To generate GraphQL interfaces and unions as Go interfaces with concrete struct per possible type provide discriminated_unions option.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -discriminated_unions
+
+To generate struct of each named GraphQL fragment embedded in all structs spreading the fragment provide fragment_structs option.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -fragment_structs
+
+To precompute hashes of operations used as automatic persisted queries provide persisted_queries option.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -persisted_queries
+
+To generate persisted operations manifest next to the generated client provide persisted_manifest option together with persisted_queries option.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -persisted_queries -persisted_manifest
+
+To customize generated code provide template_dir option with templates overriding embedded templates of the same name (i.e. struct.tmpl, interface_impl.tmpl).
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -template_dir=./tools/grafik_templates
+
+To add struct tags with the JSON name of the field to all fields of generated structs provide field_tag option for each tag key.
+Example:
+	grafikgen -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -field_tag=db -field_tag=yaml
+
+To generate multiple clients in one run describe them in grafik.yaml config file and provide config option.
+grafik.yaml in the current directory is used when neither config nor schema_source and query_source options are provided.
+Example:
+	grafikgen -config=./tools/grafik.yaml
+
+To regenerate grafik client whenever schema or query sources change use watch sub-command with the same options.
+Example:
+	grafikgen watch -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -poll_interval=500ms
+
+To verify in CI that generated grafik client is up to date use check sub-command with the same options.
+Example:
+	grafikgen check -schema_source=./schemas/my_schema.graphql -query_source=./schemas/my_query.graphql -destination=./app/my_client.go
+
 To display this message use help:
 Example:
 	grafikgen help
@@ -36,6 +105,12 @@ Example:
 
 const rwe = 0755
 
+// checkCmd is the name of sub-command that verifies if generated grafik client is up to date.
+const checkCmd = "check"
+
+// introspectionTimeout is a timeout of introspection query sent to GraphQL endpoint.
+const introspectionTimeout = 30 * time.Second
+
 func writeFile(content io.WriterTo, fullDist string) error {
 	dir, _ := filepath.Split(fullDist)
 	if dir != "" {
@@ -79,14 +154,41 @@ func (c cli) parseClientName() string {
 	return fmt.Sprintf("Grafik%sClient", clientName)
 }
 
-// queryFileName parses GraphQL schema file name.
+// queryFileName parses GraphQL query file name.
+// If multiple query sources are provided the first one is used. For glob pattern the name of its base directory is used.
+// For Go package (directory) the name of the directory is used.
 func (c cli) queryFileName() string {
-	baseName := filepath.Base(*c.querySource)
+	src := strings.TrimSpace(strings.Split(*c.querySource, ",")[0])
+	if isGlob(src) {
+		src = globBase(src)
+	}
+	// Base name of the relative directory (i.e. ".") is resolved from the absolute path.
+	if absSrc, err := filepath.Abs(src); err == nil {
+		src = absSrc
+	}
+	baseName := filepath.Base(src)
 	fileName := strings.Split(baseName, ".")[0]
 	pFileName := strings.ReplaceAll(fileName, "-", "_")
 	return common.SentenceCase(pFileName)
 }
 
+// getTemplateDir returns the directory with custom templates or empty string if embedded templates are used.
+func (c cli) getTemplateDir() string {
+	if c.templateDir == nil {
+		return ""
+	}
+	return *c.templateDir
+}
+
+// plugins returns built-in plugins enabled by CLI arguments.
+func (c cli) plugins() []plugin.Plugin {
+	plugins := make([]plugin.Plugin, 0)
+	if len(c.fieldTags) > 0 {
+		plugins = append(plugins, plugin.FieldTags(c.fieldTags...))
+	}
+	return plugins
+}
+
 // getFileContent returns content of the file.
 func getFileContent(src *string) ([]byte, error) {
 	if src == nil || *src == "" {
@@ -103,6 +205,196 @@ func getFileContent(src *string) ([]byte, error) {
 	return ioutil.ReadFile(absSrc)
 }
 
+// expandSources splits comma separated list of sources and expands glob patterns into matching files.
+// Sources are returned in the provided order - files matched by single glob pattern are sorted. Duplicates are omitted.
+func expandSources(src string) ([]string, error) {
+	sources := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, s := range strings.Split(src, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		matches := []string{s}
+		if isGlob(s) {
+			var err error
+			matches, err = glob(s)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("pattern %s does not match any file", s)
+			}
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				sources = append(sources, m)
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("provided source path is empty")
+	}
+	return sources, nil
+}
+
+// isGlob checks if the source is glob pattern.
+func isGlob(src string) bool {
+	return strings.ContainsAny(src, "*?[")
+}
+
+// globBase returns the longest leading directory of glob pattern without any special characters.
+func globBase(pattern string) string {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	base := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if isGlob(segment) {
+			break
+		}
+		base = append(base, segment)
+	}
+	if len(base) == 0 {
+		return "."
+	}
+	if len(base) == 1 && base[0] == "" {
+		return "/"
+	}
+	return filepath.FromSlash(strings.Join(base, "/"))
+}
+
+// glob returns sorted names of all files matching the pattern.
+// In addition to filepath.Match syntax '**' segment matches any number of directories.
+func glob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return filterFiles(matches), nil
+	}
+
+	patternSegments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	matches := make([]string, 0)
+	err := filepath.Walk(globBase(pattern), func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ok, err := matchSegments(patternSegments, strings.Split(filepath.ToSlash(filepath.Clean(p)), "/"))
+		if ok {
+			matches = append(matches, p)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// matchSegments matches path segments against glob pattern segments. '**' segment matches zero or more path segments.
+func matchSegments(pattern []string, name []string) (bool, error) {
+	if len(pattern) == 0 {
+		return len(name) == 0, nil
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			ok, err := matchSegments(pattern[1:], name[i:])
+			if ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	if len(name) == 0 {
+		return false, nil
+	}
+	ok, err := filepath.Match(pattern[0], name[0])
+	if !ok || err != nil {
+		return false, err
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// filterFiles returns only paths that are not directories.
+func filterFiles(paths []string) []string {
+	files := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// getQuerySources returns all GraphQL query files as separate sources, so parsing errors point to the right file.
+// GraphQL operations embedded in Go source files and Go packages (directories) are extracted with each Go file being a separate source.
+func (c cli) getQuerySources() ([]*ast.Source, error) {
+	paths, err := expandSources(*c.querySource)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*ast.Source, 0, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			goSources, err := getGoSources(p)
+			if err != nil {
+				return nil, err
+			}
+			if len(goSources) == 0 {
+				return nil, fmt.Errorf("no GraphQL operations found in Go package %s", p)
+			}
+			sources = append(sources, goSources...)
+			continue
+		}
+		if isGoSource(p) {
+			goSources, err := getGoSources(p)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, goSources...)
+			continue
+		}
+		content, err := getFileContent(&p)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, &ast.Source{Name: p, Input: string(content)})
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no GraphQL operations found in Go source files")
+	}
+	return sources, nil
+}
+
+// getGoSources returns GraphQL operations embedded in Go source file or all Go files of the package if src is directory.
+// Go files without embedded operations are omitted.
+func getGoSources(src string) ([]*ast.Source, error) {
+	files := []string{src}
+	if !isGoSource(src) {
+		var err error
+		files, err = getGoPackageFiles(src)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sources := make([]*ast.Source, 0, len(files))
+	for _, f := range files {
+		ops, err := extractOperations(f)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(ops) != "" {
+			sources = append(sources, &ast.Source{Name: f, Input: ops})
+		}
+	}
+	return sources, nil
+}
+
 // getFileDestName returns destination file name - either defined via CLI flag or same as client name.
 func (c cli) getFileDestName(clientName string) string {
 	dist := c.destination
@@ -112,6 +404,115 @@ func (c cli) getFileDestName(clientName string) string {
 	return fmt.Sprintf("%s.go", filepath.Join(*dist, clientName))
 }
 
+// scalarBindings is a flag.Value that collects custom GraphQL scalars bound to Go types in the form of Name=[import/path.]TypeName.
+type scalarBindings map[string]string
+
+// String returns all scalar bindings sorted by name and separated by comma.
+func (s scalarBindings) String() string {
+	bindings := make([]string, 0, len(s))
+	for name, goType := range s {
+		bindings = append(bindings, fmt.Sprintf("%s=%s", name, goType))
+	}
+	sort.Strings(bindings)
+	return strings.Join(bindings, ",")
+}
+
+// Set parses and validates single scalar binding.
+func (s scalarBindings) Set(v string) error {
+	binding := strings.SplitN(v, "=", 2)
+	if len(binding) != 2 || binding[0] == "" {
+		return fmt.Errorf("invalid scalar binding %q - expected Name=[import/path.]TypeName", v)
+	}
+	if _, err := evaluator.ParseGoTypeRef(binding[1]); err != nil {
+		return err
+	}
+	s[binding[0]] = binding[1]
+	return nil
+}
+
+// fieldTags is a flag.Value that collects keys of struct tags added to all fields of generated structs.
+type fieldTags []string
+
+// String returns all keys separated by comma.
+func (f *fieldTags) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set adds single key of struct tag.
+func (f *fieldTags) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// schemaHeaders is a flag.Value that collects HTTP headers sent with introspection query in the form of Name: Value.
+type schemaHeaders http.Header
+
+// String returns all headers sorted by name and separated by comma.
+func (h schemaHeaders) String() string {
+	headers := make([]string, 0, len(h))
+	for name, values := range h {
+		for _, value := range values {
+			headers = append(headers, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	sort.Strings(headers)
+	return strings.Join(headers, ",")
+}
+
+// Set parses and validates single HTTP header.
+func (h schemaHeaders) Set(v string) error {
+	header := strings.SplitN(v, ":", 2)
+	if len(header) != 2 || strings.TrimSpace(header[0]) == "" {
+		return fmt.Errorf("invalid schema header %q - expected Name: Value", v)
+	}
+	http.Header(h).Add(strings.TrimSpace(header[0]), strings.TrimSpace(header[1]))
+	return nil
+}
+
+// getSchemaSources returns all GraphQL schema sources as SDL.
+// Each schema source is either GraphQL endpoint queried via introspection, JSON introspection result file or SDL file - file sources can be glob patterns.
+func (c cli) getSchemaSources() ([]*ast.Source, error) {
+	paths, err := expandSources(*c.schemaSource)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*ast.Source, len(paths))
+	for i, p := range paths {
+		content, err := c.getSchemaContent(p)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = &ast.Source{Name: p, Input: string(content)}
+	}
+	return sources, nil
+}
+
+// getSchemaContent returns GraphQL schema SDL.
+// Schema source is either GraphQL endpoint queried via introspection, JSON introspection result file or SDL file.
+func (c cli) getSchemaContent(src string) ([]byte, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		httpClient := &http.Client{Timeout: introspectionTimeout}
+		schema, err := introspection.Fetch(context.Background(), src, http.Header(c.schemaHeader), httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(schema.SDL()), nil
+	}
+
+	content, err := getFileContent(&src)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(src), ".json") {
+		schema, err := introspection.Parse(content)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(schema.SDL()), nil
+	}
+	return content, nil
+}
+
 // usage prints help usage text.
 func usage(fs *flag.FlagSet) {
 	_, _ = io.WriteString(os.Stdout, usageTxt)
@@ -0,0 +1,117 @@
+// Package main provides grafikgen CLI tools used for generating grafik clients.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchCmd is the name of sub-command that regenerates grafik clients on changes of schema and query sources.
+const watchCmd = "watch"
+
+// sourcesState is the state of schema and query source files - modification time and size of each file.
+type sourcesState map[string]string
+
+// watchClients generates grafik clients and regenerates each of them whenever its schema or query sources change.
+// Sources are polled with the interval. Generation errors are logged and do not stop watching. Watching stops when stop is closed.
+func watchClients(clis []cli, interval time.Duration, logger *log.Logger, stop <-chan struct{}) {
+	states := make([]sourcesState, len(clis))
+	for i, c := range clis {
+		states[i] = c.sourcesState()
+		c.regenerate(logger)
+	}
+	logger.Printf("Watching for changes of GraphQL schema and query sources...")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for i, c := range clis {
+				state := c.sourcesState()
+				if state.equal(states[i]) {
+					continue
+				}
+				states[i] = state
+				c.regenerate(logger)
+			}
+		}
+	}
+}
+
+// regenerate generates grafik client and logs the result of the generation.
+func (c cli) regenerate(logger *log.Logger) {
+	if err := c.write(); err != nil {
+		logger.Printf("Failed to generate grafik client. Cause: %s", strings.TrimSpace(err.Error()))
+		return
+	}
+	logger.Printf("Generated grafik client %s", c.getFileDestName(c.parseClientName()))
+}
+
+// sourcesState returns current state of all schema and query source files and custom templates. Glob patterns are expanded on each call, so new files are detected.
+// GraphQL endpoints are not watched. Missing files are part of the state, so their creation is detected.
+func (c cli) sourcesState() sourcesState {
+	state := make(sourcesState)
+	for _, src := range []string{*c.schemaSource, *c.querySource} {
+		paths, err := expandSources(src)
+		if err != nil {
+			continue
+		}
+		for _, p := range paths {
+			if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+				continue
+			}
+			info, err := os.Stat(p)
+			if err != nil {
+				state[p] = ""
+				continue
+			}
+			if !info.IsDir() {
+				state[p] = fileState(info)
+				continue
+			}
+			goFiles, err := getGoPackageFiles(p)
+			if err != nil {
+				continue
+			}
+			for _, f := range goFiles {
+				if info, err := os.Stat(f); err == nil {
+					state[f] = fileState(info)
+				}
+			}
+		}
+	}
+	if templateDir := c.getTemplateDir(); templateDir != "" {
+		templates, _ := filepath.Glob(filepath.Join(templateDir, "*.tmpl"))
+		for _, f := range templates {
+			if info, err := os.Stat(f); err == nil {
+				state[f] = fileState(info)
+			}
+		}
+	}
+	return state
+}
+
+// fileState returns state of single file - its modification time and size.
+func fileState(info os.FileInfo) string {
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// equal checks if both states contain the same files in the same state.
+func (s sourcesState) equal(other sourcesState) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for f, state := range s {
+		if otherState, ok := other[f]; !ok || otherState != state {
+			return false
+		}
+	}
+	return true
+}
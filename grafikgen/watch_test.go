@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchClients(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	schema, err := ioutil.ReadFile("../test/fragment/schema.graphql")
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "schema.graphql"), schema, 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "queries"), 0700))
+	queryFileName := filepath.Join(dir, "queries", "query.graphql")
+	assert.NoError(t, ioutil.WriteFile(queryFileName, []byte("query getRockets {\n    rockets {\n        id\n    }\n}\n"), 0600))
+
+	c := cli{
+		schemaSource: strPtr(filepath.Join(dir, "schema.graphql")),
+		querySource:  strPtr(filepath.Join(dir, "queries", "*.graphql")),
+		packageName:  strPtr("rockets"),
+		clientName:   strPtr("RocketClient"),
+		destination:  strPtr(filepath.Join(dir, "client.go")),
+		usePointers:  boolPtr(false),
+		nullablePtrs: boolPtr(false),
+		perOpTypes:   boolPtr(false),
+		optInputs:    boolPtr(false),
+		discUnions:   boolPtr(false),
+		fragStructs:  boolPtr(false),
+		persisted:    boolPtr(false),
+		manifest:     boolPtr(false),
+	}
+
+	out := &syncBuffer{}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watchClients([]cli{c}, 10*time.Millisecond, log.New(out, "", 0), stop)
+		close(done)
+	}()
+
+	waitForLog(t, out, "Watching for changes of GraphQL schema and query sources...")
+	assert.Contains(t, out.String(), "Generated grafik client "+*c.destination)
+	assertFileContains(t, *c.destination, "Id string `json:\"id\"`")
+
+	// Invalid query is reported without stopping watching.
+	writeSource(t, queryFileName, "query getRockets {\n    rockets {\n        launched\n    }\n}\n")
+	waitForLog(t, out, "Failed to generate grafik client. Cause: failed to parse GraphQL query file. Cause: "+queryFileName+":3: Cannot query field \"launched\" on type \"Rocket\".")
+
+	writeSource(t, queryFileName, "query getRockets {\n    rockets {\n        name\n    }\n}\n")
+	waitForLog(t, out, "on type \"Rocket\".\nGenerated grafik client "+*c.destination)
+	waitForFile(t, *c.destination, "Name string `json:\"name\"`")
+
+	// New file matching glob pattern is detected.
+	writeSource(t, filepath.Join(dir, "queries", "names.graphql"), "query getRocketNames {\n    rockets {\n        name\n    }\n}\n")
+	waitForFile(t, *c.destination, "getRocketNames")
+
+	close(stop)
+	<-done
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// writeSource writes the file and moves its modification time forward, so the change is detected regardless of file system time resolution.
+func writeSource(t *testing.T, fileName string, content string) {
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(content), 0600))
+	mtime := time.Now().Add(time.Duration(len(content)) * time.Second)
+	assert.NoError(t, os.Chtimes(fileName, mtime, mtime))
+}
+
+func waitForLog(t *testing.T, out *syncBuffer, exp string) {
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), exp)
+	}, 5*time.Second, 10*time.Millisecond, "expected log %q, got %q", exp, out.String())
+}
+
+func waitForFile(t *testing.T, fileName string, exp string) {
+	assert.Eventually(t, func() bool {
+		content, err := ioutil.ReadFile(fileName)
+		return err == nil && strings.Contains(string(content), exp)
+	}, 5*time.Second, 10*time.Millisecond, "expected %s to contain %q", fileName, exp)
+}
+
+func assertFileContains(t *testing.T, fileName string, exp string) {
+	content, err := ioutil.ReadFile(fileName)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), exp)
+}
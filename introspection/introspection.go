@@ -0,0 +1,198 @@
+// Package introspection contains the logic responsible for loading GraphQL schema from introspection result.
+// Introspection result is either fetched from live GraphQL endpoint or read from JSON file and converted to GraphQL SDL.
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Bartosz-D3V/grafik/client"
+	"net/http"
+)
+
+// Query is the standard GraphQL introspection query.
+const Query = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Schema represents __Schema type of introspection result.
+type Schema struct {
+	QueryType        *NamedType  `json:"queryType"`
+	MutationType     *NamedType  `json:"mutationType"`
+	SubscriptionType *NamedType  `json:"subscriptionType"`
+	Types            []FullType  `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+// NamedType represents reference to the type by its name.
+type NamedType struct {
+	Name string `json:"name"`
+}
+
+// FullType represents __Type type of introspection result with all its fields.
+type FullType struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+// Field represents __Field type of introspection result.
+type Field struct {
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+// InputValue represents __InputValue type of introspection result.
+// DefaultValue is GraphQL literal - i.e. "10" or "\"EU\"".
+type InputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+// EnumValue represents __EnumValue type of introspection result.
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+// TypeRef represents reference to the type - wrapping types (NON_NULL & LIST) reference the wrapped type with OfType.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// Directive represents __Directive type of introspection result.
+type Directive struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Locations   []string     `json:"locations"`
+	Args        []InputValue `json:"args"`
+}
+
+// result represents introspection result in both supported shapes - either wrapped in data or not.
+type result struct {
+	Data *struct {
+		Schema *Schema `json:"__schema"`
+	} `json:"data"`
+	Schema *Schema `json:"__schema"`
+}
+
+// Parse parses JSON introspection result. Both the full GraphQL response ({"data": {"__schema": ...}}) and bare result ({"__schema": ...}) are supported.
+func Parse(b []byte) (*Schema, error) {
+	var res result
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	if res.Data != nil && res.Data.Schema != nil {
+		return res.Data.Schema, nil
+	}
+	if res.Schema != nil {
+		return res.Schema, nil
+	}
+	return nil, errors.New("introspection result does not contain __schema")
+}
+
+// Fetch executes introspection query against GraphQL endpoint. The header is sent with the request - i.e. to provide authorization.
+func Fetch(ctx context.Context, endpoint string, header http.Header, httpClient *http.Client) (*Schema, error) {
+	res, err := client.New(endpoint, httpClient).Execute(ctx, Query, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var out result
+	if err := client.DecodeResponse(res, &out); err != nil {
+		return nil, err
+	}
+	if out.Data == nil || out.Data.Schema == nil {
+		return nil, errors.New("introspection result does not contain __schema")
+	}
+	return out.Data.Schema, nil
+}
@@ -0,0 +1,191 @@
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Bartosz-D3V/grafik/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser"
+	"github.com/vektah/gqlparser/ast"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	b := loadIntrospection(t)
+
+	schema, err := Parse(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "Query", schema.QueryType.Name)
+	assert.Equal(t, "Mutation", schema.MutationType.Name)
+	assert.Nil(t, schema.SubscriptionType)
+	assert.Len(t, schema.Types, 16)
+	assert.Len(t, schema.Directives, 4)
+}
+
+func TestParse_BareSchema(t *testing.T) {
+	t.Parallel()
+	schema, err := Parse([]byte(`{"__schema": {"queryType": {"name": "Query"}, "types": [], "directives": []}}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Schema{QueryType: &NamedType{Name: "Query"}, Types: []FullType{}, Directives: []Directive{}}, schema)
+}
+
+func TestParse_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input  string
+		expErr string
+	}{
+		{`{"data": {}}`, "introspection result does not contain __schema"},
+		{`{"data":`, "unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		schema, err := Parse([]byte(test.input))
+		assert.Nil(t, schema)
+		assert.EqualError(t, err, test.expErr)
+	}
+}
+
+func TestSchema_SDL(t *testing.T) {
+	t.Parallel()
+	schema, err := Parse(loadIntrospection(t))
+	assert.NoError(t, err)
+
+	expOut := `schema {
+    query: Query
+    mutation: Mutation
+}
+
+"Caches the field."
+directive @cached(ttl: Int = 60) on FIELD_DEFINITION | OBJECT
+
+type Query {
+    rockets(find: RocketFind, limit: Int = 10): [Rocket!]!
+    vehicle(id: ID!): Vehicle
+}
+
+type Mutation {
+    launch(id: ID!): Rocket
+}
+
+interface Node {
+    id: ID!
+}
+
+"Rocket built by SpaceX."
+type Rocket implements Node {
+    id: ID!
+    "Name of the \"rocket\"."
+    name: String
+    status: Status
+    launchedAt: DateTime
+    mass: Float @deprecated(reason: "Use massKg.")
+    massKg: Float
+}
+
+type Ship implements Node {
+    id: ID!
+    port: String
+}
+
+union Vehicle = Rocket | Ship
+
+enum Status {
+    ACTIVE
+    "No longer flying."
+    RETIRED
+    UNKNOWN @deprecated
+}
+
+input RocketFind {
+    name: String
+    status: Status = ACTIVE
+    ids: [ID!]
+}
+
+"ISO-8601 date time."
+scalar DateTime
+`
+	assert.Equal(t, expOut, schema.SDL())
+
+	astSchema, gqlErr := gqlparser.LoadSchema(&ast.Source{Input: schema.SDL()})
+	assert.Nil(t, gqlErr)
+	assert.Equal(t, ast.Object, astSchema.Types["Rocket"].Kind)
+	assert.Equal(t, ast.Union, astSchema.Types["Vehicle"].Kind)
+	assert.Equal(t, "Query", astSchema.Query.Name)
+}
+
+func TestSchema_SDL_NoRootTypes(t *testing.T) {
+	t.Parallel()
+	schema, err := Parse([]byte(`{"__schema": {"types": [{"kind": "OBJECT", "name": "Query", "fields": [{"name": "id", "args": [], "type": {"kind": "SCALAR", "name": "ID"}}]}], "directives": []}}`))
+	assert.NoError(t, err)
+
+	expOut := `type Query {
+    id: ID
+}
+`
+	assert.Equal(t, expOut, schema.SDL())
+
+	astSchema, gqlErr := gqlparser.LoadSchema(&ast.Source{Input: schema.SDL()})
+	assert.Nil(t, gqlErr)
+	assert.Equal(t, "Query", astSchema.Query.Name)
+
+	empty, err := Parse([]byte(`{"__schema": {"types": [], "directives": []}}`))
+	assert.NoError(t, err)
+	assert.Empty(t, empty.SDL())
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+	b := loadIntrospection(t)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		reqBytes, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var req client.GraphQLRequest
+		assert.NoError(t, json.Unmarshal(reqBytes, &req))
+		assert.Equal(t, Query, req.Query)
+
+		_, err = w.Write(b)
+		assert.NoError(t, err)
+	}))
+	defer svr.Close()
+
+	header := http.Header{"Authorization": {"Bearer token"}}
+	schema, err := Fetch(context.TODO(), svr.URL, header, svr.Client())
+
+	assert.NoError(t, err)
+	expSchema, err := Parse(b)
+	assert.NoError(t, err)
+	assert.Equal(t, expSchema, schema)
+}
+
+func TestFetch_Error(t *testing.T) {
+	t.Parallel()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `{"errors": [{"message": "Introspection is disabled"}]}`)
+	}))
+	defer svr.Close()
+
+	schema, err := Fetch(context.TODO(), svr.URL, nil, svr.Client())
+
+	assert.Nil(t, schema)
+	var resErr client.GraphQLResponseError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, http.StatusForbidden, resErr.StatusCode)
+	assert.Equal(t, []client.GraphQLError{{Message: "Introspection is disabled"}}, resErr.Errors)
+}
+
+func loadIntrospection(t *testing.T) []byte {
+	b, err := ioutil.ReadFile("../test/introspection/schema.json")
+	assert.NoError(t, err)
+	return b
+}
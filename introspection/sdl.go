@@ -0,0 +1,186 @@
+// Package introspection contains the logic responsible for loading GraphQL schema from introspection result.
+// Introspection result is either fetched from live GraphQL endpoint or read from JSON file and converted to GraphQL SDL.
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// builtInTypes are types defined by GraphQL specification - they are already part of every schema loaded by gqlparser.
+var builtInTypes = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// builtInDirectives are directives defined by GraphQL specification - they are already part of every schema loaded by gqlparser.
+var builtInDirectives = map[string]bool{
+	"include":     true,
+	"skip":        true,
+	"deprecated":  true,
+	"specifiedBy": true,
+}
+
+// SDL converts introspection result to GraphQL Schema Definition Language.
+// Built-in scalars, directives and introspection types are omitted.
+// Schema definition is written only if the result defines any root operation type - otherwise types named Query, Mutation and Subscription are used by convention.
+// Diagnostics of the schema loaded from the SDL point at the lines of the generated SDL, not at the introspection result.
+func (s *Schema) SDL() string {
+	var sb strings.Builder
+
+	if s.QueryType != nil || s.MutationType != nil || s.SubscriptionType != nil {
+		sb.WriteString("schema {\n")
+		if s.QueryType != nil {
+			fmt.Fprintf(&sb, "    query: %s\n", s.QueryType.Name)
+		}
+		if s.MutationType != nil {
+			fmt.Fprintf(&sb, "    mutation: %s\n", s.MutationType.Name)
+		}
+		if s.SubscriptionType != nil {
+			fmt.Fprintf(&sb, "    subscription: %s\n", s.SubscriptionType.Name)
+		}
+		sb.WriteString("}\n")
+	}
+
+	for _, d := range s.Directives {
+		if builtInDirectives[d.Name] {
+			continue
+		}
+		writeSeparator(&sb)
+		writeDescription(&sb, "", d.Description)
+		fmt.Fprintf(&sb, "directive @%s%s on %s\n", d.Name, argsSDL(d.Args), strings.Join(d.Locations, " | "))
+	}
+
+	for _, t := range s.Types {
+		if builtInTypes[t.Name] || strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		writeSeparator(&sb)
+		writeDescription(&sb, "", t.Description)
+		writeType(&sb, t)
+	}
+
+	return sb.String()
+}
+
+// writeSeparator writes blank line separating definitions - unless it is the first definition.
+func writeSeparator(sb *strings.Builder) {
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+}
+
+// writeType writes GraphQL type definition based on its kind.
+func writeType(sb *strings.Builder, t FullType) {
+	switch t.Kind {
+	case "SCALAR":
+		fmt.Fprintf(sb, "scalar %s\n", t.Name)
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if t.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		fmt.Fprintf(sb, "%s %s", keyword, t.Name)
+		if len(t.Interfaces) > 0 {
+			names := make([]string, len(t.Interfaces))
+			for i, iface := range t.Interfaces {
+				names[i] = iface.Name
+			}
+			fmt.Fprintf(sb, " implements %s", strings.Join(names, " & "))
+		}
+		sb.WriteString(" {\n")
+		for _, f := range t.Fields {
+			writeDescription(sb, "    ", f.Description)
+			fmt.Fprintf(sb, "    %s%s: %s%s\n", f.Name, argsSDL(f.Args), f.Type.SDL(), deprecatedSDL(f.IsDeprecated, f.DeprecationReason))
+		}
+		sb.WriteString("}\n")
+	case "UNION":
+		names := make([]string, len(t.PossibleTypes))
+		for i, possibleType := range t.PossibleTypes {
+			names[i] = possibleType.Name
+		}
+		fmt.Fprintf(sb, "union %s = %s\n", t.Name, strings.Join(names, " | "))
+	case "ENUM":
+		fmt.Fprintf(sb, "enum %s {\n", t.Name)
+		for _, v := range t.EnumValues {
+			writeDescription(sb, "    ", v.Description)
+			fmt.Fprintf(sb, "    %s%s\n", v.Name, deprecatedSDL(v.IsDeprecated, v.DeprecationReason))
+		}
+		sb.WriteString("}\n")
+	case "INPUT_OBJECT":
+		fmt.Fprintf(sb, "input %s {\n", t.Name)
+		for _, f := range t.InputFields {
+			writeDescription(sb, "    ", f.Description)
+			fmt.Fprintf(sb, "    %s\n", f.SDL())
+		}
+		sb.WriteString("}\n")
+	}
+}
+
+// SDL returns GraphQL type reference - i.e. "[String!]!".
+func (t TypeRef) SDL() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType == nil {
+			return ""
+		}
+		return t.OfType.SDL() + "!"
+	case "LIST":
+		if t.OfType == nil {
+			return ""
+		}
+		return "[" + t.OfType.SDL() + "]"
+	default:
+		return t.Name
+	}
+}
+
+// SDL returns GraphQL input value definition - i.e. "limit: Int = 10".
+func (v InputValue) SDL() string {
+	out := fmt.Sprintf("%s: %s", v.Name, v.Type.SDL())
+	if v.DefaultValue != nil {
+		out += " = " + *v.DefaultValue
+	}
+	return out
+}
+
+// argsSDL returns GraphQL arguments definition - i.e. "(id: ID!, limit: Int = 10)".
+func argsSDL(args []InputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+	defs := make([]string, len(args))
+	for i, arg := range args {
+		defs[i] = arg.SDL()
+	}
+	return "(" + strings.Join(defs, ", ") + ")"
+}
+
+// deprecatedSDL returns @deprecated directive if the field or enum value is deprecated.
+func deprecatedSDL(isDeprecated bool, reason *string) string {
+	if !isDeprecated {
+		return ""
+	}
+	if reason == nil {
+		return " @deprecated"
+	}
+	return fmt.Sprintf(" @deprecated(reason: %s)", stringSDL(*reason))
+}
+
+// writeDescription writes description of the definition as GraphQL string.
+func writeDescription(sb *strings.Builder, indent string, description string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(sb, "%s%s\n", indent, stringSDL(description))
+}
+
+// stringSDL returns GraphQL string literal. JSON string escaping is compatible with GraphQL string escaping.
+func stringSDL(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,44 @@
+// Package plugin defines hooks that modify intermediate model of grafik client before it is rendered by generator.
+package plugin
+
+import (
+	"fmt"
+	"github.com/Bartosz-D3V/grafik/ds"
+	"strings"
+)
+
+// fieldTags is built-in plugin that adds struct tags of given keys to all fields of public structs.
+type fieldTags struct {
+	keys []string
+}
+
+// FieldTags returns plugin that adds struct tags of given keys to all fields of public structs - i.e. db:"mission_name".
+// Value of each tag is the JSON name of the field. Fields omitted from JSON (json:"-") are skipped.
+func FieldTags(keys ...string) Plugin {
+	return fieldTags{keys: keys}
+}
+
+// Name returns the name of the plugin.
+func (p fieldTags) Name() string {
+	return "field_tags"
+}
+
+// Mutate adds struct tags to all fields of public structs.
+func (p fieldTags) Mutate(m *Model) error {
+	for _, key := range p.keys {
+		if key == "" || key == "json" || strings.ContainsAny(key, " :\"`") {
+			return fmt.Errorf("invalid struct tag key %q", key)
+		}
+	}
+	for _, s := range m.Types {
+		for i, f := range s.Fields {
+			if f.JsonName == "" || f.JsonName == "-" {
+				continue
+			}
+			for _, key := range p.keys {
+				s.Fields[i].Tags = append(s.Fields[i].Tags, ds.Tag{Key: key, Value: f.JsonName})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"github.com/Bartosz-D3V/grafik/ds"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFieldTags(t *testing.T) {
+	t.Parallel()
+	m := &Model{
+		Types: []*ds.Struct{
+			{
+				Name: "RocketInput",
+				Fields: []ds.TypeField{
+					{Name: "name", Type: "string", JsonName: "name"},
+					{Name: "costPerLaunch", Type: "int", JsonName: "cost_per_launch", Tags: []ds.Tag{{Key: "validate", Value: "min=0"}}},
+					{Name: "NullFields", Type: "[]string", JsonName: "-"},
+				},
+			},
+		},
+	}
+
+	p := FieldTags("db", "yaml")
+	assert.Equal(t, "field_tags", p.Name())
+	assert.NoError(t, p.Mutate(m))
+	assert.Equal(t, []ds.TypeField{
+		{Name: "name", Type: "string", JsonName: "name", Tags: []ds.Tag{{Key: "db", Value: "name"}, {Key: "yaml", Value: "name"}}},
+		{Name: "costPerLaunch", Type: "int", JsonName: "cost_per_launch", Tags: []ds.Tag{{Key: "validate", Value: "min=0"}, {Key: "db", Value: "cost_per_launch"}, {Key: "yaml", Value: "cost_per_launch"}}},
+		{Name: "NullFields", Type: "[]string", JsonName: "-"},
+	}, m.Types[0].Fields)
+}
+
+func TestFieldTags_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		key    string
+		expErr string
+	}{
+		{"", "invalid struct tag key \"\""},
+		{"json", "invalid struct tag key \"json\""},
+		{"db:", "invalid struct tag key \"db:\""},
+		{"my tag", "invalid struct tag key \"my tag\""},
+	}
+
+	for _, test := range tests {
+		assert.EqualError(t, FieldTags(test.key).Mutate(&Model{}), test.expErr)
+	}
+}
@@ -0,0 +1,34 @@
+// Package plugin defines hooks that modify intermediate model of grafik client before it is rendered by generator.
+package plugin
+
+import "github.com/Bartosz-D3V/grafik/ds"
+
+// Model is the intermediate model of grafik client built by evaluator - data structures passed to generator templates.
+// Plugins can modify them - i.e. add struct tags, rename struct fields or add methods of generated types.
+type Model struct {
+	// PackageName is the name of the package of generated Go code.
+	PackageName string
+	// ClientName is the name of generated grafik client interface.
+	ClientName string
+	// Types are public structs - GraphQL object, input, interface & union types and operation response types.
+	// Types can be modified, appended or removed. Renamed type must be renamed in the fields referencing it as well.
+	// Response types of operations (<Operation>Response, <Operation>Event and <Operation>Data) cannot be renamed or removed, as generated methods reference them.
+	Types []*ds.Struct
+	// Enums are GraphQL enums. Enums can be modified, appended or removed.
+	Enums []*ds.Enum
+	// Operations are methods of grafik client executing GraphQL operations. Operations can be modified, but not appended or removed.
+	// Name of the operation cannot be changed - it names the query constant and response types as well.
+	// Name of the argument is the name of GraphQL variable, so it should not be changed.
+	// Changes of WrapperTypes have no effect - <Operation>Data struct is rendered from Types, so it must be modified there.
+	Operations []*ds.Func
+	// Declarations are Go declarations appended to generated code - i.e. methods of generated types.
+	Declarations []string
+}
+
+// Plugin modifies intermediate model of grafik client before it is rendered.
+type Plugin interface {
+	// Name returns the name of the plugin used in error messages.
+	Name() string
+	// Mutate modifies the model. Returned error stops the generation.
+	Mutate(m *Model) error
+}
@@ -0,0 +1,6 @@
+package go_source
+
+// launchesQuery returns the query of the launches with the rocket used for the mission.
+func launchesQuery() string {
+	return /* GraphQL */ "query getLaunches { launches { mission_name rocket { ...RocketInfo } } }"
+}
@@ -0,0 +1,19 @@
+// Package go_source contains GraphQL operations embedded in Go source files.
+package go_source
+
+// getRockets is the query of the rockets with their basic information.
+const getRockets = /* GraphQL */ `
+query getRockets($limit: Int) {
+    rockets(limit: $limit) {
+        ...RocketInfo
+    }
+}`
+
+//grafik:query
+// fragment RocketInfo on Rocket {
+//     id
+//     name
+// }
+
+// notGraphQL is not extracted, because it is not marked with GraphQL comment.
+const notGraphQL = `query notGraphQL { rockets { id } }`